@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	cmd2 "github.com/Quanghng/url-shortener/cmd"
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/spf13/cobra"
+)
+
+// Flags de la commande 'audit'
+var (
+	auditActorFlag      string
+	auditActionFlag     string
+	auditTargetTypeFlag string
+	auditTargetFlag     string
+	auditFromFlag       string
+	auditToFlag         string
+	auditLimitFlag      int
+	auditOffsetFlag     int
+)
+
+// AuditCmd consulte le journal d'audit des actions d'administration.
+var AuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Consulte le journal d'audit des actions d'administration.",
+	Long: `Cette commande affiche les entrées du journal d'audit, de la plus récente à la plus ancienne :
+créations, modifications, suppressions, désactivations de liens (y compris par le moniteur),
+actions de modération et purges de la corbeille, avec leur auteur et les valeurs modifiées.
+
+Une action terminée par "." désigne une famille (--action=link. : toutes les actions sur les liens).
+
+Exemples:
+  url-shortener audit --target=xyz123
+  url-shortener audit --actor=system:monitor --from=2025-06-01 --to=2025-06-30
+  url-shortener audit --action=link.deleted --limit=100`,
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase(cmd2.Cfg)
+		defer closeDB()
+
+		auditService := services.NewAuditService(repository.NewAuditRepository(db))
+		entries, err := auditService.ListEntries(services.AuditQuery{
+			Actor:      auditActorFlag,
+			Action:     auditActionFlag,
+			TargetType: auditTargetTypeFlag,
+			Target:     auditTargetFlag,
+			From:       auditFromFlag,
+			To:         auditToFlag,
+			Limit:      auditLimitFlag,
+			Offset:     auditOffsetFlag,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidAuditQuery) {
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			log.Fatalf("FATAL: consultation du journal d'audit: %v", err)
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("Aucune entrée.")
+			return
+		}
+		for _, entry := range entries {
+			fmt.Printf("%s  %-20s %-19s %s %s\n", entry.CreatedAt.Format("2006-01-02 15:04:05"),
+				entry.Actor, entry.Action, entry.TargetType, entry.Target)
+			for _, line := range formatAuditDiff(entry.Diff) {
+				fmt.Println("    " + line)
+			}
+			if entry.Note != "" {
+				fmt.Printf("    note: %s\n", entry.Note)
+			}
+		}
+	},
+}
+
+// formatAuditDiff présente les champs modifiés par ordre alphabétique : "champ: ancienne -> nouvelle".
+func formatAuditDiff(diff models.AuditDiff) []string {
+	fields := make([]string, 0, len(diff))
+	for field := range diff {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	lines := make([]string, 0, len(fields))
+	for _, field := range fields {
+		change := diff[field]
+		if change.Old == nil {
+			lines = append(lines, fmt.Sprintf("%s: %s", field, formatAuditValue(change.New)))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s -> %s", field, formatAuditValue(change.Old), formatAuditValue(change.New)))
+	}
+	return lines
+}
+
+// formatAuditValue rend visibles les valeurs absentes ou vides d'un champ modifié.
+func formatAuditValue(value interface{}) string {
+	switch value {
+	case nil:
+		return "(vide)"
+	case "":
+		return `""`
+	}
+	return fmt.Sprint(value)
+}
+
+func init() {
+	AuditCmd.Flags().StringVar(&auditActorFlag, "actor", "", "Filtre par auteur (ex: admin, cli:alice, system:monitor)")
+	AuditCmd.Flags().StringVar(&auditActionFlag, "action", "", "Filtre par action (ex: link.deleted) ou famille (ex: link.)")
+	AuditCmd.Flags().StringVar(&auditTargetTypeFlag, "target-type", "", "Filtre par type de cible (link, report, domain, webhook)")
+	AuditCmd.Flags().StringVar(&auditTargetFlag, "target", "", "Filtre par cible ([domaine/]code court, n° de signalement, domaine)")
+	AuditCmd.Flags().StringVar(&auditFromFlag, "from", "", "Début de la période (2025-06-01 ou RFC 3339)")
+	AuditCmd.Flags().StringVar(&auditToFlag, "to", "", "Fin de la période (date incluse ou RFC 3339)")
+	AuditCmd.Flags().IntVar(&auditLimitFlag, "limit", 50, "Nombre maximal d'entrées affichées (500 au plus)")
+	AuditCmd.Flags().IntVar(&auditOffsetFlag, "offset", 0, "Nombre d'entrées à ignorer (pagination)")
+
+	cmd2.RootCmd.AddCommand(AuditCmd)
+}
@@ -1,24 +1,79 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/url" // Pour valider le format de l'URL
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	cmd2 "github.com/Quanghng/url-shortener/cmd"
+	"github.com/Quanghng/url-shortener/internal/config"
+	"github.com/Quanghng/url-shortener/internal/metadata"
 	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/policy"
 	"github.com/Quanghng/url-shortener/internal/repository"
+	"github.com/Quanghng/url-shortener/internal/reputation"
 	"github.com/Quanghng/url-shortener/internal/services"
-	"github.com/glebarez/sqlite" // Driver SQLite pur Go (CGO-free) pour GORM
+	"github.com/Quanghng/url-shortener/internal/shortcode"
+	"github.com/Quanghng/url-shortener/internal/workers"
 	"github.com/spf13/cobra"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 // TODO : Faire une variable longURLFlag qui stockera la valeur du flag --url
 var longURLFlag string
 
+// Flag --password : mot de passe facultatif protégeant la redirection
+var passwordFlag string
+
+// Flag --max-clicks : nombre maximal de redirections (0 = illimité, 1 = usage unique)
+var maxClicksFlag int
+
+// Flags d'activation programmée
+var (
+	startsAtFlag    string   // Date d'activation (RFC 3339 ou "AAAA-MM-JJ HH:MM" dans --timezone)
+	timezoneFlag    string   // Fuseau horaire IANA des plages horaires et de --starts-at
+	windowFlags     []string // Plages horaires ("mon-fri 09:00-18:00"), répétables
+	fallbackURLFlag string   // URL de repli en dehors de la période d'activation
+)
+
+// Flags des paramètres de requête ajoutés à la destination
+var (
+	utmFlags          models.UTM // --utm-source, --utm-medium, --utm-campaign, --utm-term, --utm-content
+	forwardQueryFlag  bool       // Transmet les paramètres de requête du visiteur à la destination
+	queryConflictFlag string     // Règle de conflit des paramètres transmis (keep, override, append)
+)
+
+// Flags d'organisation du lien
+var (
+	tagFlags     []string // Étiquettes, répétables
+	campaignFlag string   // Campagne
+	folderFlag   string   // Dossier ("marketing/2025")
+)
+
+// Flags --og-title, --og-description, --og-image : aperçu servi aux robots des messageries et réseaux sociaux
+var socialCardFlags models.SocialCard
+
+// Flag --dedupe : réutilise un lien existant vers la même destination (par défaut : dedupe.enabled), partagé par create et import
+var dedupeFlag bool
+
+// Flag --domain : domaine personnalisé du lien (server.domains), partagé par create, stats, qr et export
+var domainFlag string
+
+// Flags de routage, répétables :
+//   - --device : règles par plateforme ou appareil ("ios=itms-apps://...", "mobile=https://m.example.com") ;
+//   - --geo    : règles par pays ("FR,BE=https://example.fr") ;
+//   - --variant : variantes d'un test A/B ("A:70=https://a.example.com").
+var (
+	deviceRuleFlags []string
+	geoRuleFlags    []string
+	variantFlags    []string
+)
+
 // CreateCmd représente la commande 'create'
 var CreateCmd = &cobra.Command{
 	Use:   "create",
@@ -26,7 +81,16 @@ var CreateCmd = &cobra.Command{
 	Long: `Cette commande raccourcit une URL longue fournie et affiche le code court généré.
 
 Exemple:
-  url-shortener create --url="https://www.google.com/search?q=go+lang"`,
+  url-shortener create --url="https://www.google.com/search?q=go+lang"
+  url-shortener create --url="https://example.com/doc" --password="s3cret"
+  url-shortener create --url="https://example.com/promo" --starts-at="2025-06-01 09:00" \
+    --timezone="Europe/Paris" --window="mon-fri 09:00-18:00" --fallback-url="https://example.com"
+  url-shortener create --url="https://example.com" --geo="FR,BE=https://example.fr"
+  url-shortener create --url="https://example.com" --device="ios=itms-apps://apps.apple.com/app/id123" \
+    --device="android=market://details?id=com.example.app"
+  url-shortener create --url="https://example.com" --variant="A:70=https://a.example.com" --variant="B:30=https://b.example.com"
+  url-shortener create --url="https://example.com" --utm-source=newsletter --utm-medium=email --forward-query
+  url-shortener create --url="https://example.com" --tag=promo --tag=ete --campaign="Soldes 2025" --folder=marketing/2025`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// TODO 1: Valider que le flag --url a été fourni.
 		if longURLFlag == "" {
@@ -41,47 +105,161 @@ Exemple:
 			os.Exit(1)
 		}
 
-		// Charger la configuration chargée globalement via cmd.cfg
-		cfg := cmd2.Cfg
+		// Même longueur de mot de passe que l'API (bcrypt ne prend en compte que les 72 premiers octets)
+		if passwordFlag != "" && (len(passwordFlag) < 4 || len(passwordFlag) > 72) {
+			fmt.Fprintln(os.Stderr, "Mot de passe invalide: --password doit contenir entre 4 et 72 caractères")
+			os.Exit(1)
+		}
 
-		// Initialiser la connexion à la base de données SQLite avec logger silencieux
-		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{
-			Logger: logger.Default.LogMode(logger.Silent),
-		})
+		// Lire les options d'activation programmée
+		startsAt, schedule, err := parseScheduleFlags()
 		if err != nil {
-			log.Fatalf("FATAL: Échec ouverture DB: %v", err)
+			fmt.Fprintf(os.Stderr, "Programmation invalide: %v\n", err)
+			os.Exit(1)
 		}
 
-		sqlDB, err := db.DB()
+		// Lire les règles de routage par appareil et par pays
+		deviceRules, err := parseDeviceRuleFlags()
 		if err != nil {
-			log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+			fmt.Fprintf(os.Stderr, "Règle par appareil invalide: %v\n", err)
+			os.Exit(1)
+		}
+		geoRules, err := parseGeoRuleFlags()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Règle géographique invalide: %v\n", err)
+			os.Exit(1)
+		}
+		variants, err := parseVariantFlags()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Variante invalide: %v\n", err)
+			os.Exit(1)
 		}
 
-		// TODO S'assurer que la connexion est fermée à la fin de l'exécution de la commande
-		defer sqlDB.Close()
+		// Charger la configuration chargée globalement via cmd.cfg
+		cfg := cmd2.Cfg
 
-		// AutoMigrate pour s'assurer des schémas minimums
-		if err := db.AutoMigrate(&models.Link{}, &models.Click{}); err != nil {
-			log.Fatalf("FATAL: migration: %v", err)
-		}
+		// Initialiser la connexion à la base de données SQLite (fermée à la fin de la commande)
+		db, closeDB := openDatabase(cfg)
+		defer closeDB()
 
 		// TODO : Initialiser les repositories et services nécessaires NewLinkRepository & NewLinkService
-		linkRepo := repository.NewLinkRepository(db)
-		linkService := services.NewLinkService(linkRepo)
+		linkService := newLinkService(db, cfg)
 
 		// TODO : Appeler le LinkService et la fonction CreateLink pour créer le lien court.
 		// os.Exit(1) si erreur
-		link, err := linkService.CreateLink(longURLFlag)
+		var dedupe *bool
+		if cmd.Flags().Changed("dedupe") {
+			dedupe = &dedupeFlag
+		}
+		link, created, err := linkService.CreateLink(longURLFlag, services.CreateLinkOptions{
+			Password:    passwordFlag,
+			MaxClicks:   maxClicksFlag,
+			StartsAt:    startsAt,
+			Schedule:    schedule,
+			FallbackURL: fallbackURLFlag,
+			DeviceRules: deviceRules,
+			GeoRules:    geoRules,
+			Variants:    variants,
+
+			UTM:           &utmFlags,
+			ForwardQuery:  forwardQueryFlag,
+			QueryConflict: queryConflictFlag,
+
+			Tags:     tagFlags,
+			Campaign: campaignFlag,
+			Folder:   folderFlag,
+			Domain:   domainFlag,
+
+			SocialCard: socialCardFlags,
+
+			Dedupe: dedupe,
+			Actor:  actorFlag,
+		})
+		if errors.Is(err, services.ErrInvalidMaxClicks) {
+			fmt.Fprintf(os.Stderr, "Limite de clics invalide (--max-clicks): %v\n", err)
+			os.Exit(1)
+		}
+		if errors.Is(err, policy.ErrDestinationNotAllowed) || errors.Is(err, services.ErrDestinationFlagged) ||
+			errors.Is(err, services.ErrInvalidSchedule) ||
+			errors.Is(err, services.ErrInvalidRoutingRule) ||
+			errors.Is(err, services.ErrInvalidQueryOption) ||
+			errors.Is(err, services.ErrInvalidGrouping) ||
+			errors.Is(err, services.ErrInvalidDomain) ||
+			errors.Is(err, services.ErrInvalidSocialCard) {
+			fmt.Fprintf(os.Stderr, "URL refusée: %v\n", err)
+			os.Exit(1)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Échec de création du lien: %v\n", err)
 			os.Exit(1)
 		}
 
-		fullShortURL := fmt.Sprintf("%s/%s", cfg.Server.BaseURL, link.ShortCode)
+		fullShortURL := linkService.ShortURL(link)
+		if !created {
+			// Déduplication : un lien existant pointe déjà vers cette destination
+			fmt.Printf("Lien existant réutilisé pour cette destination:\n")
+			fmt.Printf("Code: %s\n", link.ShortCode)
+			fmt.Printf("URL longue: %s\n", link.LongURL)
+			fmt.Printf("URL complète: %s\n", fullShortURL)
+			return
+		}
 		fmt.Printf("URL courte créée avec succès:\n")
 		fmt.Printf("Code: %s\n", link.ShortCode)
 		fmt.Printf("URL longue: %s\n", link.LongURL)
 		fmt.Printf("URL complète: %s\n", fullShortURL)
+		if link.IsPasswordProtected() {
+			fmt.Println("Protégé par mot de passe: oui")
+		}
+		if link.MaxClicks > 0 {
+			fmt.Printf("Redirections maximales: %d\n", link.MaxClicks)
+		}
+		if link.StartsAt != nil {
+			fmt.Printf("Actif à partir du: %s\n", link.StartsAt.Format(time.RFC3339))
+		}
+		if link.Schedule != nil {
+			fmt.Printf("Plages horaires (%s): %s\n", link.Schedule.Timezone, strings.Join(windowFlags, " ; "))
+		}
+		for _, rule := range link.DeviceRules {
+			fmt.Printf("Appareils %s -> %s\n", strings.Join(rule.Targets, ","), rule.URL)
+		}
+		for _, rule := range link.GeoRules {
+			fmt.Printf("Pays %s -> %s\n", strings.Join(rule.Countries, ","), rule.URL)
+		}
+		if len(link.Tags) > 0 {
+			names := make([]string, 0, len(link.Tags))
+			for _, tag := range link.Tags {
+				names = append(names, tag.Name)
+			}
+			fmt.Printf("Étiquettes: %s\n", strings.Join(names, ", "))
+		}
+		if link.Campaign != nil {
+			fmt.Printf("Campagne: %s\n", link.Campaign.Name)
+		}
+		if link.Folder != "" {
+			fmt.Printf("Dossier: %s\n", link.Folder)
+		}
+		if params := link.UTM.Values(); len(params) > 0 {
+			fmt.Printf("Paramètres UTM: %s\n", params.Encode())
+		}
+		if link.ForwardQuery {
+			fmt.Printf("Transmission des paramètres de requête: oui (conflits: %s)\n", link.QueryConflict)
+		}
+		for _, variant := range link.Variants {
+			fmt.Printf("Variante %s (poids %d) -> %s\n", variant.Name, variant.Weight, variant.URL)
+		}
+
+		// Sans workers en arrière-plan, la CLI récupère les métadonnées de la destination immédiatement
+		if cfg.Metadata.Enabled {
+			meta, err := fetchLinkMetadata(db, cfg, link)
+			switch {
+			case err != nil:
+				log.Printf("Attention: enregistrement des métadonnées impossible: %v", err)
+			case meta.Error != "":
+				fmt.Printf("Métadonnées indisponibles: %s\n", meta.Error)
+			case meta.Title != "":
+				fmt.Printf("Titre de la page: %s\n", meta.Title)
+			}
+		}
 	},
 }
 
@@ -93,8 +271,169 @@ func init() {
 
 	// TODO :  Marquer le flag comme requis
 	_ = CreateCmd.MarkFlagRequired("url")
+	CreateCmd.Flags().StringVar(&passwordFlag, "password", "", "Mot de passe facultatif (4 à 72 caractères) exigé avant la redirection")
+	CreateCmd.Flags().IntVar(&maxClicksFlag, "max-clicks", 0, "Nombre maximal de redirections (0 = illimité, 1 = usage unique)")
+	CreateCmd.Flags().StringVar(&startsAtFlag, "starts-at", "", "Date d'activation (RFC 3339 ou \"AAAA-MM-JJ HH:MM\" dans --timezone)")
+	CreateCmd.Flags().StringVar(&timezoneFlag, "timezone", "UTC", "Fuseau horaire IANA des plages horaires (ex: Europe/Paris)")
+	CreateCmd.Flags().StringArrayVar(&windowFlags, "window", nil, "Plage horaire d'ouverture, répétable (ex: \"mon-fri 09:00-18:00\")")
+	CreateCmd.Flags().StringVar(&fallbackURLFlag, "fallback-url", "", "URL de repli en dehors de la période d'activation")
+	CreateCmd.Flags().StringArrayVar(&tagFlags, "tag", nil, "Étiquette du lien, répétable")
+	CreateCmd.Flags().StringVar(&campaignFlag, "campaign", "", "Campagne du lien")
+	CreateCmd.Flags().StringVar(&folderFlag, "folder", "", "Dossier du lien (ex: marketing/2025)")
+	CreateCmd.Flags().StringVar(&domainFlag, "domain", "", "Domaine personnalisé du lien, parmi server.domains (ex: go.acme.io)")
+	CreateCmd.Flags().StringVar(&utmFlags.Source, "utm-source", "", "Paramètre utm_source ajouté à la destination")
+	CreateCmd.Flags().StringVar(&utmFlags.Medium, "utm-medium", "", "Paramètre utm_medium ajouté à la destination")
+	CreateCmd.Flags().StringVar(&utmFlags.Campaign, "utm-campaign", "", "Paramètre utm_campaign ajouté à la destination")
+	CreateCmd.Flags().StringVar(&utmFlags.Term, "utm-term", "", "Paramètre utm_term ajouté à la destination")
+	CreateCmd.Flags().StringVar(&utmFlags.Content, "utm-content", "", "Paramètre utm_content ajouté à la destination")
+	CreateCmd.Flags().BoolVar(&forwardQueryFlag, "forward-query", false, "Transmet les paramètres de requête du visiteur à la destination")
+	CreateCmd.Flags().StringVar(&queryConflictFlag, "query-conflict", "keep", "Conflit de paramètres transmis : keep, override ou append")
+	CreateCmd.Flags().StringArrayVar(&deviceRuleFlags, "device", nil, "Routage par plateforme ou appareil, répétable (ex: \"ios=itms-apps://apps.apple.com/app/id123\")")
+	CreateCmd.Flags().StringArrayVar(&variantFlags, "variant", nil, "Variante de test A/B \"nom:poids=URL\", répétable (ex: \"A:70=https://a.example.com\")")
+	CreateCmd.Flags().BoolVar(&dedupeFlag, "dedupe", false, "Réutilise le lien existant vers la même destination (par défaut : dedupe.enabled)")
+	CreateCmd.Flags().StringVar(&actorFlag, "actor", defaultActor(), "Auteur de la création (journal d'audit)")
+	CreateCmd.Flags().StringVar(&socialCardFlags.Title, "og-title", "", "Titre de l'aperçu servi aux robots des messageries (og:title)")
+	CreateCmd.Flags().StringVar(&socialCardFlags.Description, "og-description", "", "Description de l'aperçu (og:description)")
+	CreateCmd.Flags().StringVar(&socialCardFlags.ImageURL, "og-image", "", "URL de l'image de l'aperçu (og:image)")
+	CreateCmd.Flags().StringArrayVar(&geoRuleFlags, "geo", nil, "Routage par pays, répétable (ex: \"FR,BE=https://example.fr\")")
 
 	// TODO : Ajouter la commande à RootCmd
 	cmd2.RootCmd.AddCommand(CreateCmd)
 
 }
+
+// newLinkService construit un LinkService configuré comme celui du serveur
+// (politique de destination, domaines bannis, étiquettes, listes de blocage).
+func newLinkService(db *gorm.DB, cfg *config.Config) *services.LinkService {
+	linkService := services.NewLinkService(repository.NewLinkRepository(db))
+	destinationPolicy := policy.NewDestinationPolicy(cfg.Security)
+	destinationPolicy.SetBanList(repository.NewBannedDomainRepository(db))
+	linkService.SetDestinationPolicy(destinationPolicy)
+	linkService.SetTagRepository(repository.NewTagRepository(db))
+	configureDomains(linkService, cfg)
+	linkService.SetDedupe(cfg.Dedupe.Enabled)
+	linkService.SetTrashRetention(time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour)
+	linkService.SetAuditLog(services.NewAuditService(repository.NewAuditRepository(db)))
+	linkService.SetWebhooks(newWebhookDispatcher(db, cfg))
+	codeGenerator, err := shortcode.New(cfg.ShortCode)
+	if err != nil {
+		log.Fatalf("FATAL: shortcode: %v", err)
+	}
+	linkService.SetShortCodeGenerator(codeGenerator)
+	if cfg.Reputation.Enabled {
+		linkService.SetReputationChecker(reputation.NewChecker(cfg.Reputation))
+	}
+	return linkService
+}
+
+// fetchLinkMetadata récupère et enregistre les métadonnées de la destination d'un lien,
+// avec le client HTTP de la politique de destination comme le serveur.
+func fetchLinkMetadata(db *gorm.DB, cfg *config.Config, link *models.Link) (models.LinkMetadata, error) {
+	destinationPolicy := policy.NewDestinationPolicy(cfg.Security)
+	destinationPolicy.SetBanList(repository.NewBannedDomainRepository(db))
+	fetcher := metadata.NewFetcher(
+		destinationPolicy.NewHTTPClient(time.Duration(cfg.Metadata.TimeoutSeconds)*time.Second),
+		int64(cfg.Metadata.MaxBytes),
+	)
+	return workers.FetchLinkMetadata(fetcher, repository.NewLinkRepository(db), models.MetadataJob{LinkID: link.ID, LongURL: link.LongURL})
+}
+
+// configureDomains définit le domaine par défaut (server.base_url) et les domaines personnalisés du LinkService.
+func configureDomains(linkService *services.LinkService, cfg *config.Config) {
+	linkService.SetBaseURL(cfg.Server.BaseURL)
+	if err := linkService.SetDomains(cfg.Server.Domains); err != nil {
+		log.Fatalf("FATAL: server.domains: %v", err)
+	}
+}
+
+// parseScheduleFlags construit la date d'activation et le calendrier à partir des flags.
+func parseScheduleFlags() (*time.Time, *models.Schedule, error) {
+	loc, err := time.LoadLocation(timezoneFlag)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fuseau horaire %q: %w", timezoneFlag, err)
+	}
+
+	var startsAt *time.Time
+	if startsAtFlag != "" {
+		t, err := time.Parse(time.RFC3339, startsAtFlag)
+		if err != nil {
+			t, err = time.ParseInLocation("2006-01-02 15:04", startsAtFlag, loc)
+			if err != nil {
+				return nil, nil, fmt.Errorf("--starts-at %q: format attendu RFC 3339 ou \"AAAA-MM-JJ HH:MM\"", startsAtFlag)
+			}
+		}
+		startsAt = &t
+	}
+
+	if len(windowFlags) == 0 {
+		return startsAt, nil, nil
+	}
+	schedule := &models.Schedule{Timezone: timezoneFlag}
+	for _, spec := range windowFlags {
+		window, err := models.ParseTimeWindow(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		schedule.Windows = append(schedule.Windows, window)
+	}
+	return startsAt, schedule, nil
+}
+
+// parseDeviceRuleFlags convertit les flags --device ("ios,tablet=https://...") en règles de routage.
+func parseDeviceRuleFlags() ([]models.DeviceRule, error) {
+	var rules []models.DeviceRule
+	for _, spec := range deviceRuleFlags {
+		targets, target, found := strings.Cut(spec, "=")
+		if !found || targets == "" || target == "" {
+			return nil, fmt.Errorf("%q (format attendu: \"ios,android=https://...\")", spec)
+		}
+		rules = append(rules, models.DeviceRule{
+			Targets: strings.Split(targets, ","),
+			URL:     strings.TrimSpace(target),
+		})
+	}
+	return rules, nil
+}
+
+// parseGeoRuleFlags convertit les flags --geo ("FR,BE=https://example.fr") en règles de routage.
+func parseGeoRuleFlags() ([]models.GeoRule, error) {
+	var rules []models.GeoRule
+	for _, spec := range geoRuleFlags {
+		countries, target, found := strings.Cut(spec, "=")
+		if !found || countries == "" || target == "" {
+			return nil, fmt.Errorf("%q (format attendu: \"FR,BE=https://...\")", spec)
+		}
+		rules = append(rules, models.GeoRule{
+			Countries: strings.Split(countries, ","),
+			URL:       strings.TrimSpace(target),
+		})
+	}
+	return rules, nil
+}
+
+// parseVariantFlags convertit les flags --variant ("A:70=https://...") en variantes de test A/B.
+// Le poids est facultatif ("A=https://..." vaut un poids de 1).
+func parseVariantFlags() ([]models.Variant, error) {
+	var variants []models.Variant
+	for _, spec := range variantFlags {
+		head, target, found := strings.Cut(spec, "=")
+		if !found || target == "" {
+			return nil, fmt.Errorf("%q (format attendu: \"A:70=https://...\")", spec)
+		}
+		name, weightSpec, hasWeight := strings.Cut(head, ":")
+		weight := 1
+		if hasWeight {
+			w, err := strconv.Atoi(strings.TrimSpace(weightSpec))
+			if err != nil {
+				return nil, fmt.Errorf("%q: poids invalide %q", spec, weightSpec)
+			}
+			weight = w
+		}
+		variants = append(variants, models.Variant{
+			Name:   strings.TrimSpace(name),
+			URL:    strings.TrimSpace(target),
+			Weight: weight,
+		})
+	}
+	return variants, nil
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"log"
+
+	"github.com/Quanghng/url-shortener/internal/config"
+	"github.com/Quanghng/url-shortener/internal/repository"
+	"github.com/glebarez/sqlite" // Driver SQLite pur Go (CGO-free) pour GORM
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openDatabase ouvre la base SQLite configurée avec un logger silencieux
+// et s'assure que le schéma est à jour. La fonction retournée ferme la connexion.
+// Toute erreur est fatale, comme pour les autres commandes CLI.
+func openDatabase(cfg *config.Config) (*gorm.DB, func()) {
+	db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		log.Fatalf("FATAL: Échec ouverture DB: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("FATAL: Échec de l'obtention de la base de données SQL sous-jacente: %v", err)
+	}
+
+	// AutoMigrate pour s'assurer des schémas minimums
+	if err := repository.AutoMigrate(db); err != nil {
+		sqlDB.Close()
+		log.Fatalf("FATAL: migration: %v", err)
+	}
+
+	return db, func() { sqlDB.Close() }
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	cmd2 "github.com/Quanghng/url-shortener/cmd"
+	"github.com/Quanghng/url-shortener/internal/repository"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/spf13/cobra"
+)
+
+// Flags de la commande 'export'
+var (
+	exportDatasetFlag string
+	exportFormatFlag  string
+	exportCodeFlag    string
+	exportFromFlag    string
+	exportToFlag      string
+	exportOutputFlag  string
+)
+
+// ExportCmd exporte les liens ou les clics pour une analyse externe (entrepôt de données).
+var ExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exporte les liens ou les clics en CSV, JSON Lines ou Parquet.",
+	Long: `Cette commande exporte les liens (--dataset=links) ou les clics (--dataset=clicks)
+ligne par ligne, sans charger toute la table en mémoire.
+
+La période (--from, --to) porte sur la date de création des liens ou sur l'horodatage des clics ;
+une date seule (2025-06-30) inclut toute la journée, une date RFC 3339 est une borne exacte.
+
+Exemples:
+  url-shortener export --dataset=clicks --format=parquet --from=2025-06-01 --to=2025-06-30
+  url-shortener export --dataset=links --format=jsonl --output=-
+  url-shortener export --code=xyz123 --output=xyz123-clicks.csv`,
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase(cmd2.Cfg)
+		defer closeDB()
+
+		linkService := services.NewLinkService(repository.NewLinkRepository(db))
+		configureDomains(linkService, cmd2.Cfg)
+		exportService := services.NewExportService(repository.NewExportRepository(db), linkService)
+		exp, err := exportService.PrepareExport(services.ExportQuery{
+			Dataset:   exportDatasetFlag,
+			Format:    exportFormatFlag,
+			ShortCode: exportCodeFlag,
+			Domain:    domainFlag,
+			From:      exportFromFlag,
+			To:        exportToFlag,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidExportQuery):
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			case errors.Is(err, services.ErrLinkNotFound):
+				fmt.Fprintf(os.Stderr, "Code court introuvable: %s\n", exportCodeFlag)
+				os.Exit(1)
+			default:
+				log.Fatalf("FATAL: préparation de l'export: %v", err)
+			}
+		}
+
+		output := exportOutputFlag
+		if output == "" {
+			output = exp.Filename()
+		}
+		var out io.Writer = os.Stdout
+		if output != "-" {
+			file, err := os.Create(output)
+			if err != nil {
+				log.Fatalf("FATAL: création du fichier d'export: %v", err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		count, err := exp.Stream(out)
+		if err != nil {
+			log.Fatalf("FATAL: export: %v", err)
+		}
+		if output != "-" {
+			fmt.Printf("%d ligne(s) exportée(s) dans %s\n", count, output)
+		}
+	},
+}
+
+func init() {
+	ExportCmd.Flags().StringVar(&exportDatasetFlag, "dataset", services.ExportClicks, "Données exportées : links ou clicks")
+	ExportCmd.Flags().StringVar(&exportFormatFlag, "format", "csv", "Format : csv, jsonl ou parquet")
+	ExportCmd.Flags().StringVar(&exportCodeFlag, "code", "", "Restreint l'export à un code court")
+	ExportCmd.Flags().StringVar(&domainFlag, "domain", "", "Domaine personnalisé du code court (vide = domaine par défaut)")
+	ExportCmd.Flags().StringVar(&exportFromFlag, "from", "", "Début de la période (2025-06-01 ou RFC 3339)")
+	ExportCmd.Flags().StringVar(&exportToFlag, "to", "", "Fin de la période, date incluse (2025-06-30) ou RFC 3339")
+	ExportCmd.Flags().StringVar(&exportOutputFlag, "output", "", "Fichier de sortie (par défaut <dataset>.<format>, \"-\" pour la sortie standard)")
+
+	cmd2.RootCmd.AddCommand(ExportCmd)
+}
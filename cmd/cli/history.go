@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	cmd2 "github.com/Quanghng/url-shortener/cmd"
+	"github.com/Quanghng/url-shortener/internal/policy"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/spf13/cobra"
+)
+
+// Flags des commandes 'update', 'history' et 'rollback'
+var (
+	revisionCodeFlag string
+	revisionURLFlag  string
+	revisionNoteFlag string
+	revisionFlag     int
+)
+
+// UpdateCmd modifie la destination d'un lien existant.
+var UpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Modifie la destination d'un lien court (modification tracée dans son historique).",
+	Long: `Cette commande remplace l'URL longue d'un lien ; le code court ne change pas.
+La nouvelle URL est vérifiée comme à la création et l'ancienne est conservée dans l'historique.
+
+Exemple:
+  url-shortener update --code="xyz123" --url="https://example.com/corrige" --note="coquille"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cmd2.Cfg
+		db, closeDB := openDatabase(cfg)
+		defer closeDB()
+
+		linkService := newLinkService(db, cfg)
+		link, rev, err := linkService.UpdateDestination(domainFlag, revisionCodeFlag, revisionURLFlag, actorFlag, revisionNoteFlag)
+		if err != nil {
+			exitRevisionError(err)
+		}
+		fmt.Printf("Destination de %s modifiée (révision %d):\n", linkService.ShortURL(link), rev.Revision)
+		fmt.Printf("  ancienne: %s\n  nouvelle: %s\n", rev.OldURL, rev.NewURL)
+	},
+}
+
+// HistoryCmd affiche l'historique des destinations d'un lien.
+var HistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Affiche l'historique des destinations d'un lien court.",
+	Long: `Cette commande liste les révisions de la destination d'un lien, de la plus ancienne
+à la plus récente ; la révision 0 est la destination d'origine.
+
+Exemple:
+  url-shortener history --code="xyz123"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cmd2.Cfg
+		db, closeDB := openDatabase(cfg)
+		defer closeDB()
+
+		linkService := newLinkService(db, cfg)
+		history, err := linkService.GetLinkHistory(domainFlag, revisionCodeFlag)
+		if err != nil {
+			exitRevisionError(err)
+		}
+
+		fmt.Printf("Historique de %s\n", linkService.ShortURL(history.Link))
+		fmt.Printf("  #0  %s  création  %s\n", history.Link.CreatedAt.Format("2006-01-02 15:04"), history.OriginalURL)
+		for _, rev := range history.Revisions {
+			fmt.Printf("  #%d  %s  par %s  %s", rev.Revision, rev.CreatedAt.Format("2006-01-02 15:04"), rev.Actor, rev.NewURL)
+			if rev.RollbackOf != nil {
+				fmt.Printf("  (retour à la révision %d)", *rev.RollbackOf)
+			}
+			if rev.Note != "" {
+				fmt.Printf("  — %s", rev.Note)
+			}
+			fmt.Println()
+		}
+		fmt.Printf("Destination actuelle: %s\n", history.Link.LongURL)
+	},
+}
+
+// RollbackCmd rétablit la destination d'une révision précédente.
+var RollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Rétablit la destination d'une révision précédente d'un lien court.",
+	Long: `Cette commande rétablit la destination d'une révision (0 = destination d'origine).
+Le retour arrière est enregistré comme une nouvelle révision.
+
+Exemple:
+  url-shortener rollback --code="xyz123" --revision=0`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cmd2.Cfg
+		db, closeDB := openDatabase(cfg)
+		defer closeDB()
+
+		linkService := newLinkService(db, cfg)
+		link, rev, err := linkService.RollbackDestination(domainFlag, revisionCodeFlag, revisionFlag, actorFlag, revisionNoteFlag)
+		if err != nil {
+			exitRevisionError(err)
+		}
+		fmt.Printf("Destination de %s rétablie (révision %d): %s\n", linkService.ShortURL(link), rev.Revision, rev.NewURL)
+	},
+}
+
+// exitRevisionError affiche l'erreur d'une commande d'historique et termine le programme.
+func exitRevisionError(err error) {
+	switch {
+	case errors.Is(err, services.ErrLinkNotFound):
+		fmt.Fprintf(os.Stderr, "Code court introuvable: %s\n", revisionCodeFlag)
+	case errors.Is(err, services.ErrRevisionNotFound), errors.Is(err, services.ErrDestinationUnchanged),
+		errors.Is(err, services.ErrShortCodeRequired), errors.Is(err, services.ErrInvalidDomain):
+		fmt.Fprintln(os.Stderr, err.Error())
+	case errors.Is(err, policy.ErrDestinationNotAllowed), errors.Is(err, services.ErrDestinationFlagged):
+		fmt.Fprintf(os.Stderr, "URL refusée: %v\n", err)
+	default:
+		log.Fatalf("FATAL: %v", err)
+	}
+	os.Exit(1)
+}
+
+func init() {
+	for _, c := range []*cobra.Command{UpdateCmd, HistoryCmd, RollbackCmd} {
+		c.Flags().StringVar(&revisionCodeFlag, "code", "", "Code court du lien")
+		c.Flags().StringVar(&domainFlag, "domain", "", "Domaine personnalisé du lien (vide = domaine par défaut)")
+		_ = c.MarkFlagRequired("code")
+	}
+	for _, c := range []*cobra.Command{UpdateCmd, RollbackCmd} {
+		c.Flags().StringVar(&revisionNoteFlag, "note", "", "Motif de la modification (historique)")
+		c.Flags().StringVar(&actorFlag, "actor", defaultActor(), "Auteur de la modification (historique)")
+	}
+	UpdateCmd.Flags().StringVar(&revisionURLFlag, "url", "", "Nouvelle URL de destination")
+	_ = UpdateCmd.MarkFlagRequired("url")
+	RollbackCmd.Flags().IntVar(&revisionFlag, "revision", 0, "Révision à rétablir (0 = destination d'origine)")
+	_ = RollbackCmd.MarkFlagRequired("revision")
+
+	cmd2.RootCmd.AddCommand(UpdateCmd, HistoryCmd, RollbackCmd)
+}
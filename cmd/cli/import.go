@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	cmd2 "github.com/Quanghng/url-shortener/cmd"
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/spf13/cobra"
+)
+
+// Flags de la commande 'import'
+var (
+	importFileFlag   string
+	importOutputFlag string
+	importAtomicFlag bool
+)
+
+// importColumns liste les colonnes reconnues dans le fichier CSV importé.
+var importColumns = map[string]bool{
+	"long_url": true, "password": true, "max_clicks": true, "starts_at": true, "fallback_url": true,
+	"tags": true, "campaign": true, "folder": true,
+	"utm_source": true, "utm_medium": true, "utm_campaign": true, "utm_term": true, "utm_content": true,
+	"forward_query": true, "query_conflict": true, "domain": true,
+	"og_title": true, "og_description": true, "og_image": true,
+}
+
+// ImportCmd crée des liens en masse à partir d'un fichier CSV.
+var ImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Crée des liens courts en masse à partir d'un fichier CSV.",
+	Long: `Cette commande lit un fichier CSV dont la première ligne nomme les colonnes.
+Seule la colonne long_url est obligatoire ; les colonnes facultatives sont :
+  password, max_clicks, starts_at (RFC 3339), fallback_url,
+  tags (séparées par ";"), campaign, folder,
+  utm_source, utm_medium, utm_campaign, utm_term, utm_content,
+  forward_query (true/false), query_conflict (keep, override, append),
+  domain (domaine personnalisé configuré dans server.domains),
+  og_title, og_description, og_image (aperçu servi aux robots des messageries).
+
+Chaque ligne est validée ; le fichier de résultats associe chaque ligne
+à son code court ou à son erreur. Avec --atomic, une seule ligne invalide annule tout l'import.
+
+Exemple:
+  url-shortener import --file=links.csv --output=results.csv --atomic`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if importFileFlag == "" {
+			fmt.Fprintln(os.Stderr, "Le flag --file est requis")
+			os.Exit(1)
+		}
+		output := importOutputFlag
+		if output == "" {
+			output = strings.TrimSuffix(importFileFlag, ".csv") + "-results.csv"
+		}
+
+		inputs, lines, err := readImportFile(importFileFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fichier CSV invalide: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg := cmd2.Cfg
+		db, closeDB := openDatabase(cfg)
+		defer closeDB()
+
+		for i := range inputs {
+			inputs[i].Options.Actor = actorFlag
+			if cmd.Flags().Changed("dedupe") {
+				inputs[i].Options.Dedupe = &dedupeFlag
+			}
+		}
+
+		linkService := newLinkService(db, cfg)
+		results, err := linkService.CreateLinks(inputs, importAtomicFlag)
+		if err != nil && !errors.Is(err, services.ErrBulkRejected) {
+			if errors.Is(err, services.ErrInvalidBulkRequest) {
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			log.Fatalf("FATAL: import: %v", err)
+		}
+
+		if err := writeImportResults(output, results, lines, linkService); err != nil {
+			log.Fatalf("FATAL: écriture des résultats: %v", err)
+		}
+
+		failed := services.CountBulkFailures(results)
+		if err != nil {
+			fmt.Printf("Import annulé : %d ligne(s) en erreur, aucun lien créé.\n", failed)
+		} else {
+			fmt.Printf("Import terminé : %d lien(s) créé(s), %d ligne(s) en erreur.\n", len(results)-failed, failed)
+		}
+		fmt.Printf("Résultats écrits dans %s\n", output)
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// readImportFile lit le fichier CSV et convertit chaque ligne en BulkLinkInput.
+// Les erreurs de conversion d'une ligne sont rattachées à la ligne (input.Error) ;
+// seules les erreurs de structure du fichier sont retournées.
+// lines contient le numéro de ligne du fichier de chaque entrée.
+func readImportFile(path string) ([]services.BulkLinkInput, []int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !importColumns[name] {
+			return nil, nil, fmt.Errorf("unknown column %q", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["long_url"]; !ok {
+		return nil, nil, errors.New("missing long_url column")
+	}
+
+	var inputs []services.BulkLinkInput
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, err
+			}
+			inputs = append(inputs, services.BulkLinkInput{Error: err.Error()})
+			lines = append(lines, parseErr.Line)
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		inputs = append(inputs, parseImportRecord(field))
+		lines = append(lines, line)
+	}
+	return inputs, lines, nil
+}
+
+// parseImportRecord convertit les colonnes d'une ligne en paramètres de création.
+func parseImportRecord(field func(name string) string) services.BulkLinkInput {
+	input := services.BulkLinkInput{
+		LongURL: field("long_url"),
+		Options: services.CreateLinkOptions{
+			Password:    field("password"),
+			FallbackURL: field("fallback_url"),
+			Campaign:    field("campaign"),
+			Folder:      field("folder"),
+			UTM: &models.UTM{
+				Source:   field("utm_source"),
+				Medium:   field("utm_medium"),
+				Campaign: field("utm_campaign"),
+				Term:     field("utm_term"),
+				Content:  field("utm_content"),
+			},
+			QueryConflict: field("query_conflict"),
+			SocialCard: models.SocialCard{
+				Title:       field("og_title"),
+				Description: field("og_description"),
+				ImageURL:    field("og_image"),
+			},
+		},
+	}
+	if input.LongURL == "" {
+		input.Error = "long_url is required"
+		return input
+	}
+	if tags := field("tags"); tags != "" {
+		input.Options.Tags = strings.Split(tags, ";")
+	}
+	if value := field("max_clicks"); value != "" {
+		maxClicks, err := strconv.Atoi(value)
+		if err != nil {
+			input.Error = fmt.Sprintf("invalid max_clicks %q", value)
+			return input
+		}
+		input.Options.MaxClicks = maxClicks
+	}
+	if value := field("starts_at"); value != "" {
+		startsAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			input.Error = fmt.Sprintf("invalid starts_at %q (expected RFC 3339)", value)
+			return input
+		}
+		input.Options.StartsAt = &startsAt
+	}
+	if value := field("forward_query"); value != "" {
+		forward, err := strconv.ParseBool(value)
+		if err != nil {
+			input.Error = fmt.Sprintf("invalid forward_query %q", value)
+			return input
+		}
+		input.Options.ForwardQuery = forward
+	}
+	return input
+}
+
+// writeImportResults écrit le fichier CSV de résultats : ligne du fichier source, URL longue,
+// code court et URL courte complète, ou erreur.
+func writeImportResults(path string, results []services.BulkResult, lines []int, linkService *services.LinkService) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	_ = writer.Write([]string{"line", "long_url", "short_code", "full_short_url", "error"})
+	for i, result := range results {
+		fullURL := ""
+		if result.Link != nil {
+			fullURL = linkService.ShortURL(result.Link)
+		}
+		_ = writer.Write([]string{strconv.Itoa(lines[i]), result.LongURL, result.ShortCode, fullURL, result.Error})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func init() {
+	ImportCmd.Flags().StringVar(&importFileFlag, "file", "", "Fichier CSV des liens à créer (colonne long_url obligatoire)")
+	ImportCmd.Flags().StringVar(&importOutputFlag, "output", "", "Fichier CSV des résultats (par défaut <fichier>-results.csv)")
+	ImportCmd.Flags().BoolVar(&importAtomicFlag, "atomic", false, "Tout ou rien : n'importe aucun lien si une ligne est invalide")
+	ImportCmd.Flags().StringVar(&actorFlag, "actor", defaultActor(), "Auteur de l'import (journal d'audit)")
+	ImportCmd.Flags().BoolVar(&dedupeFlag, "dedupe", false, "Réutilise les liens existants vers les mêmes destinations (par défaut : dedupe.enabled)")
+
+	cmd2.RootCmd.AddCommand(ImportCmd)
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	cmd2 "github.com/Quanghng/url-shortener/cmd"
+	"github.com/Quanghng/url-shortener/internal/repository"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/spf13/cobra"
+)
+
+// Flags de filtrage partagés par 'list' et 'stats'
+var (
+	filterTagFlag      string
+	filterCampaignFlag string
+	filterFolderFlag   string
+)
+
+// Flags de pagination de 'list'
+var (
+	listLimitFlag  int
+	listOffsetFlag int
+)
+
+// ListCmd liste les liens, filtrés par étiquette, campagne ou dossier.
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Liste les liens courts, filtrés par étiquette, campagne ou dossier.",
+	Long: `Cette commande liste les liens du plus récent au plus ancien.
+Les filtres se cumulent ; un dossier inclut ses sous-dossiers.
+
+Exemples:
+  url-shortener list --tag=promo
+  url-shortener list --campaign="Soldes 2025" --limit=100
+  url-shortener list --folder=marketing`,
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase(cmd2.Cfg)
+		defer closeDB()
+
+		linkService := services.NewLinkService(repository.NewLinkRepository(db))
+		links, err := linkService.ListLinks(currentLinkFilter(listLimitFlag, listOffsetFlag))
+		if err != nil {
+			log.Fatalf("FATAL: récupération des liens: %v", err)
+		}
+
+		if len(links) == 0 {
+			fmt.Println("Aucun lien.")
+			return
+		}
+		for _, link := range links {
+			line := fmt.Sprintf("%s -> %s (créé le %s)", link.ShortCode, link.LongURL, link.CreatedAt.Format("2006-01-02"))
+			var details []string
+			if len(link.Tags) > 0 {
+				names := make([]string, 0, len(link.Tags))
+				for _, tag := range link.Tags {
+					names = append(names, tag.Name)
+				}
+				details = append(details, "étiquettes: "+strings.Join(names, ","))
+			}
+			if link.Campaign != nil {
+				details = append(details, "campagne: "+link.Campaign.Name)
+			}
+			if link.Folder != "" {
+				details = append(details, "dossier: "+link.Folder)
+			}
+			if len(details) > 0 {
+				line += " [" + strings.Join(details, " ; ") + "]"
+			}
+			fmt.Println(line)
+			if link.Metadata.Title != "" {
+				fmt.Printf("    « %s »\n", link.Metadata.Title)
+			}
+		}
+	},
+}
+
+// currentLinkFilter construit le filtre à partir des flags --tag, --campaign et --folder.
+func currentLinkFilter(limit, offset int) repository.LinkFilter {
+	return repository.LinkFilter{
+		Tag:      filterTagFlag,
+		Campaign: filterCampaignFlag,
+		Folder:   filterFolderFlag,
+		Limit:    limit,
+		Offset:   offset,
+	}
+}
+
+// addLinkFilterFlags déclare les flags de filtrage sur une commande.
+func addLinkFilterFlags(c *cobra.Command) {
+	c.Flags().StringVar(&filterTagFlag, "tag", "", "Filtre par étiquette")
+	c.Flags().StringVar(&filterCampaignFlag, "campaign", "", "Filtre par campagne")
+	c.Flags().StringVar(&filterFolderFlag, "folder", "", "Filtre par dossier (sous-dossiers inclus)")
+}
+
+func init() {
+	addLinkFilterFlags(ListCmd)
+	ListCmd.Flags().IntVar(&listLimitFlag, "limit", 50, "Nombre maximal de liens affichés (500 au plus)")
+	ListCmd.Flags().IntVar(&listOffsetFlag, "offset", 0, "Nombre de liens à ignorer (pagination)")
+
+	cmd2.RootCmd.AddCommand(ListCmd)
+}
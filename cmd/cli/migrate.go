@@ -5,7 +5,7 @@ import (
 	"log"
 
 	cmd2 "github.com/Quanghng/url-shortener/cmd"
-	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
 	"github.com/spf13/cobra"
 	"gorm.io/driver/sqlite" // Driver SQLite pour GORM
 	"gorm.io/gorm"
@@ -16,8 +16,8 @@ var MigrateCmd = &cobra.Command{
 	Use:   "migrate",
 	Short: "Exécute les migrations de la base de données pour créer ou mettre à jour les tables.",
 	Long: `Cette commande se connecte à la base de données configurée (SQLite)
-et exécute les migrations automatiques de GORM pour créer les tables 'links' et 'clicks'
-basées sur les modèles Go.`,
+et exécute les migrations automatiques de GORM pour créer les tables de l'application
+('links', 'clicks', 'reports', ...) basées sur les modèles Go.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Charge la configuration globale
 		cfg := cmd2.Cfg
@@ -36,7 +36,7 @@ basées sur les modèles Go.`,
 		defer sqlDB.Close()
 
 		// Exécute les migrations automatiques de GORM pour créer les tables
-		err = db.AutoMigrate(&models.Link{}, &models.Click{})
+		err = repository.AutoMigrate(db)
 		if err != nil {
 			log.Fatalf("FATAL: Échec des migrations: %v", err)
 		}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	cmd2 "github.com/Quanghng/url-shortener/cmd"
+	"github.com/Quanghng/url-shortener/internal/qr"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/spf13/cobra"
+)
+
+// Flags de la commande 'qr'
+var (
+	qrCodeFlag   string
+	qrOutputFlag string
+	qrFormatFlag string
+	qrSizeFlag   int
+	qrMarginFlag int
+	qrECCFlag    string
+)
+
+// QRCmd écrit le QR code d'un lien court dans un fichier image.
+var QRCmd = &cobra.Command{
+	Use:   "qr",
+	Short: "Génère le QR code d'un lien court (PNG ou SVG).",
+	Long: `Cette commande écrit dans un fichier le QR code de l'URL courte complète
+d'un lien, construite à partir de server.base_url ou du domaine personnalisé du lien.
+L'URL encodée se termine par "?src=qr" : les scans apparaissent sous la provenance "qr"
+dans les statistiques du lien.
+
+Exemples:
+  url-shortener qr --code="xyz123"
+  url-shortener qr --code="xyz123" --format=svg --size=1024 --margin=2 --ecc=H --output=affiche.svg`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if qrCodeFlag == "" {
+			fmt.Fprintln(os.Stderr, "Le flag --code est requis")
+			os.Exit(1)
+		}
+
+		cfg := cmd2.Cfg
+		db, closeDB := openDatabase(cfg)
+		defer closeDB()
+
+		linkService := newLinkService(db, cfg)
+		code, err := linkService.GenerateQRCode(domainFlag, qrCodeFlag, qr.Options{
+			Format: qrFormatFlag,
+			Size:   qrSizeFlag,
+			Margin: &qrMarginFlag,
+			ECC:    qrECCFlag,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, qr.ErrInvalidOptions), errors.Is(err, services.ErrInvalidDomain):
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			case errors.Is(err, services.ErrLinkNotFound):
+				fmt.Fprintf(os.Stderr, "Code court introuvable: %s\n", qrCodeFlag)
+				os.Exit(1)
+			default:
+				log.Fatalf("FATAL: génération du QR code: %v", err)
+			}
+		}
+
+		output := qrOutputFlag
+		if output == "" {
+			output = qrCodeFlag + "." + qrFormatFlag
+		}
+		if err := os.WriteFile(output, code.Image, 0o644); err != nil {
+			log.Fatalf("FATAL: écriture du QR code: %v", err)
+		}
+		fmt.Printf("QR code de %s écrit dans %s\n", code.URL, output)
+	},
+}
+
+func init() {
+	QRCmd.Flags().StringVar(&qrCodeFlag, "code", "", "Code court du lien")
+	QRCmd.Flags().StringVar(&domainFlag, "domain", "", "Domaine personnalisé du lien (vide = domaine par défaut)")
+	QRCmd.Flags().StringVar(&qrOutputFlag, "output", "", "Fichier image (par défaut <code>.<format>)")
+	QRCmd.Flags().StringVar(&qrFormatFlag, "format", qr.FormatPNG, "Format de l'image : png ou svg")
+	QRCmd.Flags().IntVar(&qrSizeFlag, "size", qr.DefaultSize, "Côté de l'image en pixels")
+	QRCmd.Flags().IntVar(&qrMarginFlag, "margin", qr.DefaultMargin, "Marge (zone de silence) en modules")
+	QRCmd.Flags().StringVar(&qrECCFlag, "ecc", "M", "Niveau de correction d'erreur : L, M, Q ou H")
+
+	cmd2.RootCmd.AddCommand(QRCmd)
+}
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	cmd2 "github.com/Quanghng/url-shortener/cmd"
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// Flags des commandes 'reports'
+var (
+	reportStatusFlag string
+	reportIDFlag     uint
+	reportActionFlag string
+	reportNoteFlag   string
+	enableCodeFlag   string
+	actorFlag        string
+)
+
+// ReportsCmd regroupe les commandes de modération des signalements d'abus.
+var ReportsCmd = &cobra.Command{
+	Use:   "reports",
+	Short: "Gère les signalements d'abus (liste, détail, actions de modération).",
+	Long: `Cette commande permet de consulter et de traiter les signalements d'abus
+envoyés via POST /{shortCode}/report.
+
+Exemples:
+  url-shortener reports list --status=open
+  url-shortener reports show --id=3
+  url-shortener reports action --id=3 --action=disable_link --note="phishing confirmé"`,
+}
+
+// ReportsListCmd liste les signalements.
+var ReportsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Liste les signalements, éventuellement filtrés par statut.",
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase(cmd2.Cfg)
+		defer closeDB()
+
+		reportService := newReportService(db)
+		reports, err := reportService.ListReports(reportStatusFlag)
+		if err != nil {
+			log.Fatalf("FATAL: récupération des signalements: %v", err)
+		}
+
+		if len(reports) == 0 {
+			fmt.Println("Aucun signalement.")
+			return
+		}
+		for _, r := range reports {
+			fmt.Printf("#%d [%s] %s -> %s (%s) le %s\n",
+				r.ID, r.Status, r.Link.ShortCode, r.Link.LongURL, r.Reason, r.CreatedAt.Format("2006-01-02 15:04"))
+		}
+	},
+}
+
+// ReportsShowCmd affiche le détail d'un signalement et son historique de modération.
+var ReportsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Affiche un signalement et l'historique de ses actions de modération.",
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase(cmd2.Cfg)
+		defer closeDB()
+
+		reportService := newReportService(db)
+		report, actions, err := reportService.GetReport(reportIDFlag)
+		if err != nil {
+			exitOnReportError(err)
+		}
+
+		printReport(report)
+		if len(actions) == 0 {
+			fmt.Println("Aucune action de modération.")
+			return
+		}
+		fmt.Println("Historique:")
+		for _, a := range actions {
+			fmt.Printf("  %s  %s par %s", a.CreatedAt.Format("2006-01-02 15:04"), a.Action, a.Actor)
+			if a.Note != "" {
+				fmt.Printf(" (%s)", a.Note)
+			}
+			fmt.Println()
+		}
+	},
+}
+
+// ReportsActionCmd applique une action de modération à un signalement.
+var ReportsActionCmd = &cobra.Command{
+	Use:   "action",
+	Short: "Applique une action de modération : disable_link, ban_domain ou dismiss.",
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase(cmd2.Cfg)
+		defer closeDB()
+
+		reportService := newReportService(db)
+		report, err := reportService.ApplyAction(reportIDFlag, reportActionFlag, actorFlag, reportNoteFlag)
+		if err != nil {
+			exitOnReportError(err)
+		}
+
+		fmt.Printf("Action '%s' appliquée au signalement #%d.\n", reportActionFlag, report.ID)
+		printReport(report)
+	},
+}
+
+// EnableCmd réactive un lien désactivé par la modération ou signalé par la vérification de réputation.
+var EnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Réactive un lien désactivé (modération) ou signalé (listes de blocage).",
+	Long: `Cette commande lève la désactivation et le signalement de réputation d'un lien : il redirige de nouveau.
+Une destination toujours présente dans une liste de blocage est signalée de nouveau par le moniteur.
+
+Exemple:
+  url-shortener enable --code="xyz123" --note="faux positif"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cmd2.Cfg
+		db, closeDB := openDatabase(cfg)
+		defer closeDB()
+
+		linkService := newLinkService(db, cfg)
+		link, err := linkService.EnableLink(domainFlag, enableCodeFlag, actorFlag, reportNoteFlag)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrLinkNotFound):
+				fmt.Fprintf(os.Stderr, "Code court introuvable: %s\n", enableCodeFlag)
+			case errors.Is(err, services.ErrLinkNotDisabled), errors.Is(err, services.ErrShortCodeRequired),
+				errors.Is(err, services.ErrInvalidDomain):
+				fmt.Fprintln(os.Stderr, err.Error())
+			default:
+				log.Fatalf("FATAL: %v", err)
+			}
+			os.Exit(1)
+		}
+		fmt.Printf("Lien %s réactivé -> %s\n", linkService.ShortURL(link), link.LongURL)
+	},
+}
+
+// newReportService initialise le service de modération à partir de la connexion DB.
+func newReportService(db *gorm.DB) *services.ReportService {
+	reportService := services.NewReportService(
+		repository.NewReportRepository(db),
+		repository.NewLinkRepository(db),
+		repository.NewBannedDomainRepository(db),
+	)
+	reportService.SetAuditLog(services.NewAuditService(repository.NewAuditRepository(db)))
+	return reportService
+}
+
+// printReport affiche les informations principales d'un signalement.
+func printReport(r *models.Report) {
+	fmt.Printf("Signalement #%d\n", r.ID)
+	fmt.Printf("Statut: %s\n", r.Status)
+	fmt.Printf("Lien: %s -> %s (désactivé: %t)\n", r.Link.ShortCode, r.Link.LongURL, r.Link.IsDisabled)
+	fmt.Printf("Motif: %s\n", r.Reason)
+	if r.Details != "" {
+		fmt.Printf("Détails: %s\n", r.Details)
+	}
+	if r.ReporterEmail != "" {
+		fmt.Printf("Contact: %s\n", r.ReporterEmail)
+	}
+	fmt.Printf("Reçu le: %s\n", r.CreatedAt.Format("2006-01-02 15:04"))
+}
+
+// exitOnReportError affiche une erreur de modération et termine la commande.
+func exitOnReportError(err error) {
+	switch {
+	case errors.Is(err, services.ErrReportNotFound):
+		fmt.Fprintf(os.Stderr, "Signalement introuvable: #%d\n", reportIDFlag)
+	case errors.Is(err, services.ErrInvalidModerationAction):
+		fmt.Fprintf(os.Stderr, "%v (actions possibles: %s, %s, %s)\n", err,
+			models.ModerationActionDisableLink, models.ModerationActionBanDomain, models.ModerationActionDismiss)
+	case errors.Is(err, services.ErrLinkNotFound):
+		fmt.Fprintln(os.Stderr, "Le lien signalé n'existe plus (supprimé)")
+	default:
+		log.Fatalf("FATAL: %v", err)
+	}
+	os.Exit(1)
+}
+
+// defaultActor retourne l'auteur par défaut des actions effectuées en CLI (utilisateur système).
+func defaultActor() string {
+	if user := strings.TrimSpace(os.Getenv("USER")); user != "" {
+		return "cli:" + user
+	}
+	return "cli"
+}
+
+func init() {
+	ReportsListCmd.Flags().StringVar(&reportStatusFlag, "status", "", "Filtre par statut (open, actioned, dismissed)")
+
+	ReportsShowCmd.Flags().UintVar(&reportIDFlag, "id", 0, "ID du signalement")
+	_ = ReportsShowCmd.MarkFlagRequired("id")
+
+	ReportsActionCmd.Flags().UintVar(&reportIDFlag, "id", 0, "ID du signalement")
+	ReportsActionCmd.Flags().StringVar(&reportActionFlag, "action", "", "Action: disable_link, ban_domain ou dismiss")
+	ReportsActionCmd.Flags().StringVar(&reportNoteFlag, "note", "", "Commentaire facultatif")
+	ReportsActionCmd.Flags().StringVar(&actorFlag, "actor", defaultActor(), "Auteur de l'action (piste d'audit)")
+	_ = ReportsActionCmd.MarkFlagRequired("id")
+	_ = ReportsActionCmd.MarkFlagRequired("action")
+
+	EnableCmd.Flags().StringVar(&enableCodeFlag, "code", "", "Code court du lien")
+	EnableCmd.Flags().StringVar(&domainFlag, "domain", "", "Domaine personnalisé du lien (vide = domaine par défaut)")
+	EnableCmd.Flags().StringVar(&reportNoteFlag, "note", "", "Motif facultatif (journal d'audit)")
+	EnableCmd.Flags().StringVar(&actorFlag, "actor", defaultActor(), "Auteur de l'action (journal d'audit)")
+	_ = EnableCmd.MarkFlagRequired("code")
+
+	ReportsCmd.AddCommand(ReportsListCmd, ReportsShowCmd, ReportsActionCmd)
+	cmd2.RootCmd.AddCommand(ReportsCmd, EnableCmd)
+}
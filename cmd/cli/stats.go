@@ -5,21 +5,26 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
 
 	cmd2 "github.com/Quanghng/url-shortener/cmd"
-	"github.com/Quanghng/url-shortener/internal/models"
 	"github.com/Quanghng/url-shortener/internal/repository"
 	"github.com/Quanghng/url-shortener/internal/services"
 	"github.com/spf13/cobra"
 
-	"github.com/glebarez/sqlite" // Driver SQLite pur Go (CGO-free) pour GORM
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 // Flag --code
 var shortCodeFlag string
 
+// Flags des statistiques agrégées (avec --tag, --campaign ou --folder)
+var (
+	statsIntervalFlag string
+	statsDaysFlag     int
+	statsTopFlag      int
+)
+
 // StatsCmd représente la commande 'stats'
 var StatsCmd = &cobra.Command{
 	Use:   "stats",
@@ -27,45 +32,42 @@ var StatsCmd = &cobra.Command{
 	Long: `Cette commande permet de récupérer et d'afficher le nombre total de clics
 pour une URL courte spécifique en utilisant son code.
 
-Exemple:
-  url-shortener stats --code="xyz123"`,
+Avec --tag, --campaign ou --folder, elle affiche les statistiques agrégées
+de l'ensemble des liens concernés : total des clics, série temporelle et liens les plus cliqués.
+
+Exemples:
+  url-shortener stats --code="xyz123"
+  url-shortener stats --campaign="Soldes 2025" --interval=day --days=30 --top=5`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// 1) Valider flag
-		if shortCodeFlag == "" {
-			fmt.Fprintln(os.Stderr, "Le flag --code est requis")
+		filter := currentLinkFilter(0, 0)
+		if shortCodeFlag == "" && filter.IsEmpty() {
+			fmt.Fprintln(os.Stderr, "Le flag --code (ou --tag, --campaign, --folder) est requis")
 			os.Exit(1)
 		}
+		if shortCodeFlag == "" {
+			runGroupStats(filter)
+			return
+		}
 
 		// 2) Config
 		cfg := cmd2.Cfg
 
-		// 3) DB avec logger silencieux
-		db, err := gorm.Open(sqlite.Open(cfg.Database.Name), &gorm.Config{
-			Logger: logger.Default.LogMode(logger.Silent),
-		})
-		if err != nil {
-			log.Fatalf("FATAL: Échec ouverture DB: %v", err)
-		}
-		sqlDB, err := db.DB()
-		if err != nil {
-			log.Fatalf("FATAL: DB interne: %v", err)
-		}
-		defer sqlDB.Close()
-
-		// AutoMigrate minimal (si pas déjà fait)
-		if err := db.AutoMigrate(&models.Link{}, &models.Click{}); err != nil {
-			log.Fatalf("FATAL: migration: %v", err)
-		}
+		// 3) DB (logger silencieux, schéma à jour)
+		db, closeDB := openDatabase(cfg)
+		defer closeDB()
 
 		// 4) Repo + Service
 		linkRepo := repository.NewLinkRepository(db)
 		linkService := services.NewLinkService(linkRepo)
+		configureDomains(linkService, cfg)
+		clickService := services.NewClickService(repository.NewClickRepository(db))
 
 		// 5) Stats
-		link, totalClicks, err := linkService.GetLinkStats(shortCodeFlag)
+		stats, err := linkService.GetLinkStats(domainFlag, shortCodeFlag)
 		if err != nil {
 			switch {
-			case errors.Is(err, services.ErrShortCodeRequired):
+			case errors.Is(err, services.ErrShortCodeRequired), errors.Is(err, services.ErrInvalidDomain):
 				fmt.Fprintln(os.Stderr, err.Error())
 				os.Exit(1)
 			case errors.Is(err, services.ErrLinkNotFound):
@@ -79,9 +81,42 @@ Exemple:
 			}
 		}
 
+		link := stats.Link
 		fmt.Printf("Statistiques pour le code court: %s\n", link.ShortCode)
+		fmt.Printf("URL courte: %s\n", linkService.ShortURL(link))
 		fmt.Printf("URL longue: %s\n", link.LongURL)
-		fmt.Printf("Total de clics: %d\n", totalClicks)
+		fmt.Printf("Total de clics: %d\n", stats.TotalClicks)
+		variants, err := clickService.GetVariantStats(link)
+		if err != nil {
+			log.Fatalf("FATAL: clics par variante: %v", err)
+		}
+		if len(variants) > 0 {
+			fmt.Println("Clics par variante:")
+			for _, variant := range variants {
+				fmt.Printf("  %s (poids %d, %s): %d\n", variant.Name, variant.Weight, variant.URL, variant.Clicks)
+			}
+		}
+		printBreakdown(clickService, link.ID, "country", "Clics par pays")
+		printBreakdown(clickService, link.ID, "platform", "Clics par plateforme")
+		printBreakdown(clickService, link.ID, "device", "Clics par appareil")
+		printBreakdown(clickService, link.ID, "source", "Clics par provenance")
+		fmt.Printf("Accessible: %t\n", link.IsActive)
+		if link.IsPasswordProtected() {
+			fmt.Println("Protégé par mot de passe: oui")
+		}
+		if link.MaxClicks > 0 {
+			fmt.Printf("Redirections consommées: %d/%d\n", link.ClickCount, link.MaxClicks)
+		}
+		if link.TLSStatus != "" {
+			fmt.Printf("Certificat TLS: %s", link.TLSStatus)
+			if link.TLSExpiresAt != nil {
+				fmt.Printf(" (expire le %s)", link.TLSExpiresAt.Format("2006-01-02"))
+			}
+			fmt.Println()
+			if link.TLSError != "" {
+				fmt.Printf("Détail TLS: %s\n", link.TLSError)
+			}
+		}
 	},
 }
 
@@ -90,8 +125,78 @@ Exemple:
 func init() {
 	// Flag --code
 	StatsCmd.Flags().StringVar(&shortCodeFlag, "code", "", "Code court pour lequel afficher les statistiques")
-	_ = StatsCmd.MarkFlagRequired("code")
+	StatsCmd.Flags().StringVar(&domainFlag, "domain", "", "Domaine personnalisé du lien (vide = domaine par défaut)")
+	addLinkFilterFlags(StatsCmd)
+	StatsCmd.Flags().StringVar(&statsIntervalFlag, "interval", "day", "Granularité de la série temporelle : hour, day, week, month")
+	StatsCmd.Flags().IntVar(&statsDaysFlag, "days", 30, "Profondeur de la série temporelle en jours")
+	StatsCmd.Flags().IntVar(&statsTopFlag, "top", 10, "Nombre de liens les plus cliqués affichés")
 
 	cmd2.RootCmd.AddCommand(StatsCmd)
 
 }
+
+// printBreakdown affiche la répartition des clics d'un lien selon une dimension, triée par volume.
+func printBreakdown(clickService *services.ClickService, linkID uint, dimension, title string) {
+	breakdown, err := clickService.GetClicksBreakdown(linkID, dimension)
+	if err != nil {
+		log.Fatalf("FATAL: répartition des clics: %v", err)
+	}
+	if len(breakdown) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(breakdown))
+	for key := range breakdown {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if breakdown[keys[i]] != breakdown[keys[j]] {
+			return breakdown[keys[i]] > breakdown[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	fmt.Printf("%s:\n", title)
+	for _, key := range keys {
+		fmt.Printf("  %s: %d\n", key, breakdown[key])
+	}
+}
+
+// runGroupStats affiche les statistiques agrégées d'une étiquette, d'une campagne ou d'un dossier.
+func runGroupStats(filter repository.LinkFilter) {
+	db, closeDB := openDatabase(cmd2.Cfg)
+	defer closeDB()
+
+	clickService := services.NewClickService(repository.NewClickRepository(db))
+	stats, err := clickService.GetGroupStats(services.GroupStatsQuery{
+		Filter:   filter,
+		Interval: statsIntervalFlag,
+		Days:     statsDaysFlag,
+		Top:      statsTopFlag,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidStatsQuery) {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		log.Fatalf("FATAL: statistiques agrégées: %v", err)
+	}
+
+	fmt.Printf("Total de clics: %d\n", stats.TotalClicks)
+	if len(stats.Timeseries) > 0 {
+		fmt.Printf("Clics par période (%d derniers jours):\n", statsDaysFlag)
+		for _, point := range stats.Timeseries {
+			fmt.Printf("  %s: %d\n", point.Period, point.Clicks)
+		}
+	}
+	if len(stats.TopLinks) > 0 {
+		fmt.Println("Liens les plus cliqués:")
+		for _, link := range stats.TopLinks {
+			code := link.ShortCode
+			if link.Domain != "" {
+				code = link.Domain + "/" + code
+			}
+			fmt.Printf("  %s (%s): %d\n", code, link.LongURL, link.Clicks)
+		}
+	}
+}
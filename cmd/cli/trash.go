@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	cmd2 "github.com/Quanghng/url-shortener/cmd"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/spf13/cobra"
+)
+
+// Flags des commandes 'delete' et 'trash'
+var (
+	trashCodeFlag   string
+	trashLimitFlag  int
+	trashOffsetFlag int
+	trashAllFlag    bool
+)
+
+// DeleteCmd met un lien à la corbeille.
+var DeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Met un lien court à la corbeille (restaurable jusqu'à sa purge).",
+	Long: `Cette commande supprime un lien : il ne redirige plus (HTTP 410) mais reste dans la corbeille
+pendant trash.retention_days jours, durant lesquels il peut être restauré. Son code court n'est pas réattribué.
+
+Exemple:
+  url-shortener delete --code="xyz123"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cmd2.Cfg
+		db, closeDB := openDatabase(cfg)
+		defer closeDB()
+
+		linkService := newLinkService(db, cfg)
+		link, err := linkService.DeleteLink(domainFlag, trashCodeFlag, actorFlag)
+		if err != nil {
+			exitTrashError(err)
+		}
+		fmt.Printf("Lien %s mis à la corbeille.\n", linkService.ShortURL(link))
+		if purgeAt := linkService.PurgeDate(link); purgeAt != nil {
+			fmt.Printf("Restaurable jusqu'au %s (url-shortener trash restore --code=%s).\n", purgeAt.Format("2006-01-02 15:04"), link.ShortCode)
+		}
+	},
+}
+
+// TrashCmd regroupe les commandes de la corbeille.
+var TrashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Gère la corbeille des liens supprimés (liste, restauration, purge).",
+	Long: `Cette commande permet de consulter les liens supprimés, de les restaurer
+ou de les purger définitivement.
+
+Exemples:
+  url-shortener trash list --folder=marketing
+  url-shortener trash restore --code="xyz123"
+  url-shortener trash purge`,
+}
+
+// TrashListCmd liste les liens à la corbeille.
+var TrashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Liste les liens à la corbeille, du plus récemment supprimé au plus ancien.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cmd2.Cfg
+		db, closeDB := openDatabase(cfg)
+		defer closeDB()
+
+		linkService := newLinkService(db, cfg)
+		links, err := linkService.ListTrash(currentLinkFilter(trashLimitFlag, trashOffsetFlag))
+		if err != nil {
+			log.Fatalf("FATAL: récupération de la corbeille: %v", err)
+		}
+
+		if len(links) == 0 {
+			fmt.Println("La corbeille est vide.")
+			return
+		}
+		for i := range links {
+			link := &links[i]
+			line := fmt.Sprintf("%s -> %s (supprimé le %s", link.ShortCode, link.LongURL, link.DeletedAt.Time.Format("2006-01-02 15:04"))
+			if link.DeletedBy != "" {
+				line += " par " + link.DeletedBy
+			}
+			if purgeAt := linkService.PurgeDate(link); purgeAt != nil {
+				line += ", purgé le " + purgeAt.Format("2006-01-02")
+			}
+			fmt.Println(line + ")")
+		}
+	},
+}
+
+// TrashRestoreCmd sort un lien de la corbeille.
+var TrashRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restaure un lien de la corbeille ; il redirige de nouveau.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cmd2.Cfg
+		db, closeDB := openDatabase(cfg)
+		defer closeDB()
+
+		linkService := newLinkService(db, cfg)
+		link, err := linkService.RestoreLink(domainFlag, trashCodeFlag, actorFlag)
+		if err != nil {
+			exitTrashError(err)
+		}
+		fmt.Printf("Lien %s restauré -> %s\n", linkService.ShortURL(link), link.LongURL)
+	},
+}
+
+// TrashPurgeCmd supprime définitivement les liens à la corbeille.
+var TrashPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Purge les liens à la corbeille depuis plus de trash.retention_days jours (--all : tous).",
+	Long: `Cette commande supprime définitivement les liens à la corbeille, avec leurs clics et l'historique
+de leur destination ; leurs codes courts redeviennent disponibles. Le serveur effectue la même purge
+toutes les trash.purge_interval_minutes minutes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := cmd2.Cfg
+		db, closeDB := openDatabase(cfg)
+		defer closeDB()
+
+		linkService := newLinkService(db, cfg)
+		if !trashAllFlag && cfg.Trash.RetentionDays <= 0 {
+			fmt.Println("Purge automatique désactivée (trash.retention_days = 0) ; utilisez --all pour vider la corbeille.")
+			return
+		}
+		purged, err := linkService.PurgeTrash(trashAllFlag, actorFlag)
+		if err != nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+		fmt.Printf("%d lien(s) purgé(s) définitivement.\n", purged)
+	},
+}
+
+// exitTrashError affiche l'erreur d'une commande de la corbeille et termine le programme.
+func exitTrashError(err error) {
+	switch {
+	case errors.Is(err, services.ErrLinkDeleted):
+		fmt.Fprintf(os.Stderr, "Le lien %s est déjà à la corbeille\n", trashCodeFlag)
+	case errors.Is(err, services.ErrLinkNotFound):
+		fmt.Fprintf(os.Stderr, "Code court introuvable: %s\n", trashCodeFlag)
+	case errors.Is(err, services.ErrNotInTrash), errors.Is(err, services.ErrShortCodeRequired),
+		errors.Is(err, services.ErrInvalidDomain):
+		fmt.Fprintln(os.Stderr, err.Error())
+	default:
+		log.Fatalf("FATAL: %v", err)
+	}
+	os.Exit(1)
+}
+
+func init() {
+	for _, c := range []*cobra.Command{DeleteCmd, TrashRestoreCmd} {
+		c.Flags().StringVar(&trashCodeFlag, "code", "", "Code court du lien")
+		c.Flags().StringVar(&domainFlag, "domain", "", "Domaine personnalisé du lien (vide = domaine par défaut)")
+		_ = c.MarkFlagRequired("code")
+	}
+	for _, c := range []*cobra.Command{DeleteCmd, TrashRestoreCmd, TrashPurgeCmd} {
+		c.Flags().StringVar(&actorFlag, "actor", defaultActor(), "Auteur de l'action (journal d'audit)")
+	}
+
+	addLinkFilterFlags(TrashListCmd)
+	TrashListCmd.Flags().IntVar(&trashLimitFlag, "limit", 50, "Nombre maximal de liens affichés (500 au plus)")
+	TrashListCmd.Flags().IntVar(&trashOffsetFlag, "offset", 0, "Nombre de liens à ignorer (pagination)")
+
+	TrashPurgeCmd.Flags().BoolVar(&trashAllFlag, "all", false, "Purge tous les liens à la corbeille, quelle que soit leur date de suppression")
+
+	TrashCmd.AddCommand(TrashListCmd, TrashRestoreCmd, TrashPurgeCmd)
+	cmd2.RootCmd.AddCommand(DeleteCmd, TrashCmd)
+}
@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	cmd2 "github.com/Quanghng/url-shortener/cmd"
+	"github.com/Quanghng/url-shortener/internal/config"
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/policy"
+	"github.com/Quanghng/url-shortener/internal/repository"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/Quanghng/url-shortener/internal/webhooks"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// Flags des commandes 'webhooks'
+var (
+	webhookIDFlag          uint
+	webhookURLFlag         string
+	webhookEventsFlag      []string
+	webhookSecretFlag      string
+	webhookDescriptionFlag string
+	deliveryIDFlag         uint
+	deliveryStatusFlag     string
+	deliveryLimitFlag      int
+	deliveryOffsetFlag     int
+)
+
+// WebhooksCmd regroupe les commandes de gestion des webhooks.
+var WebhooksCmd = &cobra.Command{
+	Use:   "webhooks",
+	Short: "Gère les abonnements webhook et le journal de leurs livraisons.",
+	Long: `Cette commande gère les systèmes externes notifiés des événements des liens :
+link.created, link.clicked et link.health_changed. Chaque livraison est signée
+(en-tête X-Webhook-Signature) et retentée avec un délai croissant en cas d'échec.
+Les livraisons sont envoyées par le serveur (run-server).
+
+Exemples:
+  url-shortener webhooks add --url=https://hooks.acme.io/links --event=link.created --event=link.clicked
+  url-shortener webhooks deliveries --id=1 --status=failed
+  url-shortener webhooks redeliver --id=1 --delivery=42`,
+}
+
+// WebhooksListCmd liste les abonnements.
+var WebhooksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Liste les abonnements webhook.",
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase(cmd2.Cfg)
+		defer closeDB()
+
+		subs, err := newWebhookService(db, cmd2.Cfg).ListSubscriptions()
+		if err != nil {
+			log.Fatalf("FATAL: récupération des webhooks: %v", err)
+		}
+		if len(subs) == 0 {
+			fmt.Println("Aucun webhook.")
+			return
+		}
+		for _, sub := range subs {
+			line := fmt.Sprintf("#%d %s [%s] (secret %s, créé le %s)", sub.ID, sub.URL, strings.Join(sub.Events, ","),
+				services.MaskSecret(sub.Secret), sub.CreatedAt.Format("2006-01-02"))
+			if sub.Description != "" {
+				line += " - " + sub.Description
+			}
+			fmt.Println(line)
+		}
+	},
+}
+
+// WebhooksAddCmd crée un abonnement.
+var WebhooksAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Abonne une URL à des événements (le secret de signature est affiché une seule fois).",
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase(cmd2.Cfg)
+		defer closeDB()
+
+		sub, err := newWebhookService(db, cmd2.Cfg).CreateSubscription(services.WebhookInput{
+			URL:         webhookURLFlag,
+			Secret:      webhookSecretFlag,
+			Events:      webhookEventsFlag,
+			Description: webhookDescriptionFlag,
+		}, actorFlag)
+		if err != nil {
+			exitWebhookError(err)
+		}
+		fmt.Printf("Webhook #%d créé : %s [%s]\n", sub.ID, sub.URL, strings.Join(sub.Events, ","))
+		fmt.Printf("Secret de signature : %s\n", sub.Secret)
+	},
+}
+
+// WebhooksRemoveCmd supprime un abonnement.
+var WebhooksRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Supprime un abonnement webhook (son journal de livraisons est conservé).",
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase(cmd2.Cfg)
+		defer closeDB()
+
+		if err := newWebhookService(db, cmd2.Cfg).DeleteSubscription(webhookIDFlag, actorFlag); err != nil {
+			exitWebhookError(err)
+		}
+		fmt.Printf("Webhook #%d supprimé.\n", webhookIDFlag)
+	},
+}
+
+// WebhooksDeliveriesCmd affiche le journal des livraisons d'un abonnement.
+var WebhooksDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries",
+	Short: "Affiche le journal des livraisons d'un webhook, de la plus récente à la plus ancienne.",
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase(cmd2.Cfg)
+		defer closeDB()
+
+		deliveries, err := newWebhookService(db, cmd2.Cfg).ListDeliveries(webhookIDFlag, services.DeliveryQuery{
+			Status: deliveryStatusFlag,
+			Limit:  deliveryLimitFlag,
+			Offset: deliveryOffsetFlag,
+		})
+		if err != nil {
+			exitWebhookError(err)
+		}
+		if len(deliveries) == 0 {
+			fmt.Println("Aucune livraison.")
+			return
+		}
+		for _, d := range deliveries {
+			line := fmt.Sprintf("#%d %s %s %s (%d tentative(s)", d.ID, d.CreatedAt.Format("2006-01-02 15:04:05"), d.Event, d.Status, d.Attempts)
+			if d.LastStatusCode != 0 {
+				line += fmt.Sprintf(", HTTP %d", d.LastStatusCode)
+			}
+			if d.NextAttemptAt != nil && d.Status != models.WebhookDeliverySucceeded {
+				line += ", prochaine tentative le " + d.NextAttemptAt.Format("2006-01-02 15:04:05")
+			}
+			if d.RedeliveryOf != nil {
+				line += fmt.Sprintf(", nouvelle livraison de #%d", *d.RedeliveryOf)
+			}
+			fmt.Println(line + ")")
+			if d.LastError != "" {
+				fmt.Printf("    %s\n", d.LastError)
+			}
+		}
+	},
+}
+
+// WebhooksRedeliverCmd programme une nouvelle livraison d'un événement.
+var WebhooksRedeliverCmd = &cobra.Command{
+	Use:   "redeliver",
+	Short: "Programme une nouvelle livraison d'un événement (envoyée par le serveur).",
+	Run: func(cmd *cobra.Command, args []string) {
+		db, closeDB := openDatabase(cmd2.Cfg)
+		defer closeDB()
+
+		delivery, err := newWebhookService(db, cmd2.Cfg).Redeliver(webhookIDFlag, deliveryIDFlag, actorFlag)
+		if err != nil {
+			exitWebhookError(err)
+		}
+		fmt.Printf("Nouvelle livraison #%d de l'événement %s (%s) programmée.\n", delivery.ID, delivery.EventID, delivery.Event)
+	},
+}
+
+// newWebhookDispatcher crée un Dispatcher non démarré : les livraisons qu'il enregistre
+// sont envoyées par le poller du serveur.
+func newWebhookDispatcher(db *gorm.DB, cfg *config.Config) *webhooks.Dispatcher {
+	if !cfg.Webhooks.Enabled {
+		return nil
+	}
+	destinationPolicy := policy.NewDestinationPolicy(cfg.Security)
+	return webhooks.NewDispatcher(
+		repository.NewWebhookRepository(db),
+		destinationPolicy.NewHTTPClient(time.Duration(cfg.Webhooks.TimeoutSeconds)*time.Second),
+		cfg.Webhooks,
+	)
+}
+
+// newWebhookService crée le WebhookService configuré comme celui du serveur.
+func newWebhookService(db *gorm.DB, cfg *config.Config) *services.WebhookService {
+	webhookService := services.NewWebhookService(repository.NewWebhookRepository(db), newWebhookDispatcher(db, cfg))
+	destinationPolicy := policy.NewDestinationPolicy(cfg.Security)
+	destinationPolicy.SetBanList(repository.NewBannedDomainRepository(db))
+	webhookService.SetDestinationPolicy(destinationPolicy)
+	webhookService.SetAuditLog(services.NewAuditService(repository.NewAuditRepository(db)))
+	return webhookService
+}
+
+// exitWebhookError affiche l'erreur d'une commande webhook et termine le programme.
+func exitWebhookError(err error) {
+	switch {
+	case errors.Is(err, services.ErrWebhookNotFound):
+		fmt.Fprintf(os.Stderr, "Webhook introuvable: #%d\n", webhookIDFlag)
+	case errors.Is(err, services.ErrDeliveryNotFound):
+		fmt.Fprintf(os.Stderr, "Livraison introuvable: #%d\n", deliveryIDFlag)
+	case errors.Is(err, services.ErrInvalidWebhook), errors.Is(err, services.ErrInvalidDeliveryQuery),
+		errors.Is(err, services.ErrWebhooksDisabled):
+		fmt.Fprintln(os.Stderr, err.Error())
+	default:
+		log.Fatalf("FATAL: %v", err)
+	}
+	os.Exit(1)
+}
+
+func init() {
+	WebhooksAddCmd.Flags().StringVar(&webhookURLFlag, "url", "", "URL de réception des événements")
+	WebhooksAddCmd.Flags().StringSliceVar(&webhookEventsFlag, "event", nil, "Événement souscrit (link.created, link.clicked, link.health_changed), répétable")
+	WebhooksAddCmd.Flags().StringVar(&webhookSecretFlag, "secret", "", "Secret de signature (généré s'il est omis)")
+	WebhooksAddCmd.Flags().StringVar(&webhookDescriptionFlag, "description", "", "Description du webhook")
+	_ = WebhooksAddCmd.MarkFlagRequired("url")
+	_ = WebhooksAddCmd.MarkFlagRequired("event")
+
+	for _, c := range []*cobra.Command{WebhooksRemoveCmd, WebhooksDeliveriesCmd, WebhooksRedeliverCmd} {
+		c.Flags().UintVar(&webhookIDFlag, "id", 0, "ID du webhook")
+		_ = c.MarkFlagRequired("id")
+	}
+	for _, c := range []*cobra.Command{WebhooksAddCmd, WebhooksRemoveCmd, WebhooksRedeliverCmd} {
+		c.Flags().StringVar(&actorFlag, "actor", defaultActor(), "Auteur de l'action (journal d'audit)")
+	}
+
+	WebhooksDeliveriesCmd.Flags().StringVar(&deliveryStatusFlag, "status", "", "Filtre par statut (pending, succeeded, failed)")
+	WebhooksDeliveriesCmd.Flags().IntVar(&deliveryLimitFlag, "limit", 50, "Nombre maximal de livraisons affichées (500 au plus)")
+	WebhooksDeliveriesCmd.Flags().IntVar(&deliveryOffsetFlag, "offset", 0, "Nombre de livraisons à ignorer (pagination)")
+
+	WebhooksRedeliverCmd.Flags().UintVar(&deliveryIDFlag, "delivery", 0, "ID de la livraison à renvoyer")
+	_ = WebhooksRedeliverCmd.MarkFlagRequired("delivery")
+
+	WebhooksCmd.AddCommand(WebhooksListCmd, WebhooksAddCmd, WebhooksRemoveCmd, WebhooksDeliveriesCmd, WebhooksRedeliverCmd)
+	cmd2.RootCmd.AddCommand(WebhooksCmd)
+}
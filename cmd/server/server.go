@@ -12,11 +12,17 @@ import (
 
 	cmd2 "github.com/Quanghng/url-shortener/cmd"
 	"github.com/Quanghng/url-shortener/internal/api"
+	"github.com/Quanghng/url-shortener/internal/geoip"
+	"github.com/Quanghng/url-shortener/internal/metadata"
 	"github.com/Quanghng/url-shortener/internal/middleware"
 	"github.com/Quanghng/url-shortener/internal/models"
 	"github.com/Quanghng/url-shortener/internal/monitor"
+	"github.com/Quanghng/url-shortener/internal/policy"
 	"github.com/Quanghng/url-shortener/internal/repository"
+	"github.com/Quanghng/url-shortener/internal/reputation"
 	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/Quanghng/url-shortener/internal/shortcode"
+	"github.com/Quanghng/url-shortener/internal/webhooks"
 	"github.com/Quanghng/url-shortener/internal/workers"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
@@ -51,13 +57,81 @@ puis lance le serveur HTTP.`,
 		// Initialiser les repositories
 		linkRepo := repository.NewLinkRepository(db)
 		clickRepo := repository.NewClickRepository(db)
+		reportRepo := repository.NewReportRepository(db)
+		bannedDomainRepo := repository.NewBannedDomainRepository(db)
 
 		// Laissez le log
 		log.Println("Repositories initialisés.")
 
 		// Initialiser les services métiers
+		destinationPolicy := policy.NewDestinationPolicy(cfg.Security)
+		destinationPolicy.SetBanList(bannedDomainRepo)
 		linkService := services.NewLinkService(linkRepo)
-		_ = services.NewClickService(clickRepo) // clickService pas utilisé pour l'instant
+		linkService.SetDestinationPolicy(destinationPolicy)
+		linkService.SetTagRepository(repository.NewTagRepository(db))
+		linkService.SetBaseURL(cfg.Server.BaseURL)
+		if err := linkService.SetDomains(cfg.Server.Domains); err != nil {
+			log.Fatalf("Configuration server.domains invalide: %v", err)
+		}
+		codeGenerator, err := shortcode.New(cfg.ShortCode)
+		if err != nil {
+			log.Fatalf("Configuration shortcode invalide: %v", err)
+		}
+		linkService.SetShortCodeGenerator(codeGenerator)
+		linkService.SetDedupe(cfg.Dedupe.Enabled)
+		linkService.SetTrashRetention(time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour)
+		auditService := services.NewAuditService(repository.NewAuditRepository(db))
+		linkService.SetAuditLog(auditService)
+
+		// Base GeoIP pour le routage et les statistiques par pays
+		if cfg.GeoIP.DatabasePath != "" {
+			geoResolver, err := geoip.Open(cfg.GeoIP.DatabasePath)
+			if err != nil {
+				log.Printf("Attention: base GeoIP indisponible, routage par pays désactivé: %v", err)
+			} else {
+				defer geoResolver.Close()
+				linkService.SetGeoIPResolver(geoResolver)
+				log.Printf("Base GeoIP chargée depuis %s.", cfg.GeoIP.DatabasePath)
+			}
+		}
+
+		// Vérification de réputation (listes de blocage locales, rechargées périodiquement)
+		var reputationChecker *reputation.Checker
+		if cfg.Reputation.Enabled {
+			reputationChecker = reputation.NewChecker(cfg.Reputation)
+			linkService.SetReputationChecker(reputationChecker)
+			go reputationChecker.Start()
+		}
+
+		// Récupération asynchrone des métadonnées des destinations (titre, description, favicon, og:image)
+		if cfg.Metadata.Enabled {
+			metadataJobs := make(chan models.MetadataJob, cfg.Metadata.QueueSize)
+			fetcher := metadata.NewFetcher(
+				destinationPolicy.NewHTTPClient(time.Duration(cfg.Metadata.TimeoutSeconds)*time.Second),
+				int64(cfg.Metadata.MaxBytes),
+			)
+			workers.StartMetadataWorkers(cfg.Metadata.Workers, metadataJobs, fetcher, linkRepo)
+			linkService.SetMetadataQueue(metadataJobs)
+		}
+		clickService := services.NewClickService(clickRepo)
+		reportService := services.NewReportService(reportRepo, linkRepo, bannedDomainRepo)
+		reportService.SetAuditLog(auditService)
+		exportService := services.NewExportService(repository.NewExportRepository(db), linkService)
+
+		// Envoi des événements (création, clics, santé des liens) aux webhooks abonnés
+		var webhookDispatcher *webhooks.Dispatcher
+		if cfg.Webhooks.Enabled {
+			webhookDispatcher = webhooks.NewDispatcher(
+				repository.NewWebhookRepository(db),
+				destinationPolicy.NewHTTPClient(time.Duration(cfg.Webhooks.TimeoutSeconds)*time.Second),
+				cfg.Webhooks,
+			)
+			webhookDispatcher.Start(cfg.Webhooks.QueueSize)
+			linkService.SetWebhooks(webhookDispatcher)
+		}
+		webhookService := services.NewWebhookService(repository.NewWebhookRepository(db), webhookDispatcher)
+		webhookService.SetDestinationPolicy(destinationPolicy)
+		webhookService.SetAuditLog(auditService)
 
 		// Laissez le log
 		log.Println("Services métiers initialisés.")
@@ -67,20 +141,31 @@ puis lance le serveur HTTP.`,
 
 		// Lancer les workers pour traiter les événements de clic
 		numWorkers := 3
-		workers.StartClickWorkers(numWorkers, api.ClickEventsChannel, clickRepo)
+		workers.StartClickWorkers(numWorkers, api.ClickEventsChannel, clickRepo, webhookDispatcher)
 
 		log.Printf("Channel d'événements de clic initialisé avec un buffer de %d. %d worker(s) de clics démarré(s).",
 			cfg.Analytics.BufferSize, numWorkers)
 
 		// Initialiser et lancer le moniteur d'URLs
 		monitorInterval := time.Duration(cfg.Monitor.IntervalMinutes) * time.Minute
-		urlMonitor := monitor.NewUrlMonitor(linkRepo, monitorInterval)
+		tlsExpiryWindow := time.Duration(cfg.Monitor.TLSExpiryWarningDays) * 24 * time.Hour
+		urlMonitor := monitor.NewUrlMonitor(linkRepo, destinationPolicy, reputationChecker, monitorInterval, tlsExpiryWindow)
+		urlMonitor.SetAuditLog(auditService)
+		if webhookDispatcher != nil {
+			urlMonitor.SetEventPublisher(webhookDispatcher)
+		}
 
 		// Lancer le moniteur dans sa propre goroutine
 		go urlMonitor.Start()
 
 		log.Printf("Moniteur d'URLs démarré avec un intervalle de %v.", monitorInterval)
 
+		// Purge des liens restés à la corbeille au-delà de trash.retention_days
+		if cfg.Trash.RetentionDays > 0 && cfg.Trash.PurgeIntervalMinutes > 0 {
+			workers.StartTrashPurger(linkService, time.Duration(cfg.Trash.PurgeIntervalMinutes)*time.Minute)
+			log.Printf("Purge de la corbeille activée (conservation %d jour(s)).", cfg.Trash.RetentionDays)
+		}
+
 		// Configurer le routeur Gin et les handlers API (pas besoin de passer bufferSize maintenant)
 		router := gin.Default()
 		if cfg.Server.RateLimit.Requests > 0 && cfg.Server.RateLimit.WindowSeconds > 0 {
@@ -92,7 +177,20 @@ puis lance le serveur HTTP.`,
 			log.Printf("Rate limiting activé: %d requêtes / %d seconde(s).",
 				cfg.Server.RateLimit.Requests, cfg.Server.RateLimit.WindowSeconds)
 		}
-		api.SetupRoutes(router, linkService)
+		if cfg.Server.AdminToken == "" && len(cfg.Server.AdminTokens) == 0 {
+			log.Println("Attention: aucun jeton d'administration (server.admin_tokens), les routes d'administration sont désactivées (HTTP 503).")
+		}
+		passwordLimiter := middleware.NewRateLimiter(
+			cfg.Security.PasswordRateLimit.Requests,
+			time.Duration(cfg.Security.PasswordRateLimit.WindowSeconds)*time.Second,
+		)
+		api.SetupRoutes(router, linkService, clickService, reportService, exportService, auditService, webhookService, api.RouteMiddlewares{
+			AdminAuth: middleware.AdminAuth(cfg.Server.AdminToken, cfg.Server.AdminTokens),
+			// Clé IP + code court : chaque lien protégé a son propre compteur de tentatives
+			PasswordLimiter: passwordLimiter.MiddlewareByKey(func(c *gin.Context) string {
+				return c.ClientIP() + "|" + c.Param("shortCode")
+			}),
+		})
 
 		// Pas toucher au log
 		log.Println("Routes API configurées.")
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ListAuditEntriesHandler consulte le journal d'audit
+// (GET /api/v1/audit?actor=...&action=link.&target_type=link&target=xyz123&from=2025-06-01&to=2025-06-30&limit=50&offset=0).
+func ListAuditEntriesHandler(auditService *services.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.Query("limit"))
+		offset, _ := strconv.Atoi(c.Query("offset"))
+
+		entries, err := auditService.ListEntries(services.AuditQuery{
+			Actor:      c.Query("actor"),
+			Action:     c.Query("action"),
+			TargetType: c.Query("target_type"),
+			Target:     c.Query("target"),
+			From:       c.Query("from"),
+			To:         c.Query("to"),
+			Limit:      limit,
+			Offset:     offset,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidAuditQuery) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			log.Printf("Error listing audit entries: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		result := make([]gin.H, 0, len(entries))
+		for _, entry := range entries {
+			result = append(result, auditEntryJSON(entry))
+		}
+		c.JSON(http.StatusOK, gin.H{"entries": result, "count": len(result)})
+	}
+}
+
+// auditEntryJSON construit la représentation JSON d'une entrée du journal d'audit.
+func auditEntryJSON(entry models.AuditEntry) gin.H {
+	return gin.H{
+		"id":          entry.ID,
+		"created_at":  entry.CreatedAt,
+		"actor":       entry.Actor,
+		"action":      entry.Action,
+		"target_type": entry.TargetType,
+		"target":      entry.Target,
+		"diff":        entry.Diff,
+		"note":        entry.Note,
+	}
+}
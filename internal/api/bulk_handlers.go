@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Quanghng/url-shortener/internal/middleware"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// CreateBulkLinksHandler crée plusieurs liens à partir d'un tableau JSON de CreateLinkRequest
+// (POST /api/v1/links/bulk?atomic=true).
+// Chaque ligne est validée individuellement ; la réponse associe chaque ligne à son code court ou à son erreur.
+// En mode atomique, une seule ligne invalide annule l'ensemble (HTTP 422, aucun lien créé).
+func CreateBulkLinksHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var rows []CreateLinkRequest
+		if err := json.NewDecoder(c.Request.Body).Decode(&rows); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "request body must be a JSON array of links: " + err.Error()})
+			return
+		}
+		atomic := c.Query("atomic") == "true"
+
+		// Validation des tags 'binding' ligne par ligne : une ligne invalide ne rejette pas tout le lot
+		inputs := make([]services.BulkLinkInput, 0, len(rows))
+		for _, row := range rows {
+			input := services.BulkLinkInput{LongURL: row.LongURL, Options: row.options()}
+			input.Options.Actor = c.GetString(middleware.ActorKey)
+			if err := binding.Validator.ValidateStruct(&row); err != nil {
+				input.Error = err.Error()
+			}
+			inputs = append(inputs, input)
+		}
+
+		results, err := linkService.CreateLinks(inputs, atomic)
+		if errors.Is(err, services.ErrInvalidBulkRequest) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := gin.H{
+			"atomic":  atomic,
+			"results": bulkResultsJSON(linkService, results),
+			"created": len(results) - services.CountBulkFailures(results),
+			"failed":  services.CountBulkFailures(results),
+		}
+		switch {
+		case errors.Is(err, services.ErrBulkRejected):
+			response["error"] = err.Error()
+			response["created"] = 0
+			c.JSON(http.StatusUnprocessableEntity, response)
+		case err != nil:
+			log.Printf("Error creating links in bulk: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create links"})
+		default:
+			c.JSON(http.StatusOK, response)
+		}
+	}
+}
+
+// bulkResultsJSON ajoute l'URL courte complète aux résultats des lignes créées.
+func bulkResultsJSON(linkService *services.LinkService, results []services.BulkResult) []gin.H {
+	out := make([]gin.H, 0, len(results))
+	for _, result := range results {
+		item := gin.H{"row": result.Row, "long_url": result.LongURL}
+		if result.Link != nil {
+			item["short_code"] = result.ShortCode
+			item["domain"] = result.Link.Domain
+			item["full_short_url"] = linkService.ShortURL(result.Link)
+			if result.Existing {
+				item["existing"] = true // Lien existant réutilisé (déduplication)
+			}
+		}
+		if result.Error != "" {
+			item["error"] = result.Error
+		}
+		out = append(out, item)
+	}
+	return out
+}
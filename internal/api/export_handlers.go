@@ -0,0 +1,48 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler exporte les liens ou les clics au fil de l'eau
+// (GET /api/v1/export?dataset=clicks&format=parquet&code=xyz123&from=2025-06-01&to=2025-06-30).
+func ExportHandler(exportService *services.ExportService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		exp, err := exportService.PrepareExport(services.ExportQuery{
+			Dataset:   c.DefaultQuery("dataset", services.ExportClicks),
+			Format:    c.Query("format"),
+			ShortCode: c.Query("code"),
+			Domain:    c.Query("domain"),
+			From:      c.Query("from"),
+			To:        c.Query("to"),
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidExportQuery):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case errors.Is(err, services.ErrLinkNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Short link not found"})
+			default:
+				log.Printf("Error preparing export: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			}
+			return
+		}
+
+		c.Header("Content-Type", exp.ContentType())
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exp.Filename()))
+		c.Status(http.StatusOK)
+
+		// Les lignes sont envoyées au fil de la lecture : une erreur en cours d'export
+		// ne peut plus changer le statut HTTP, elle est seulement journalisée.
+		if _, err := exp.Stream(c.Writer); err != nil {
+			log.Printf("Error streaming %s export: %v", exp.Dataset, err)
+		}
+	}
+}
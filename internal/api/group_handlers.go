@@ -0,0 +1,129 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ListLinksHandler liste les liens, filtrés par étiquette, campagne ou dossier
+// (GET /api/v1/links?tag=promo&campaign=soldes&folder=marketing&limit=50&offset=0).
+func ListLinksHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.Query("limit"))
+		offset, _ := strconv.Atoi(c.Query("offset"))
+
+		links, err := linkService.ListLinks(repository.LinkFilter{
+			Tag:      c.Query("tag"),
+			Campaign: c.Query("campaign"),
+			Folder:   c.Query("folder"),
+			Limit:    limit,
+			Offset:   offset,
+		})
+		if err != nil {
+			log.Printf("Error listing links: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		result := make([]gin.H, 0, len(links))
+		for i := range links {
+			result = append(result, linkJSON(&links[i]))
+		}
+		c.JSON(http.StatusOK, gin.H{"links": result, "count": len(result)})
+	}
+}
+
+// ListTagsHandler liste les étiquettes existantes (GET /api/v1/tags).
+func ListTagsHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tags, err := linkService.ListTags()
+		if err != nil {
+			log.Printf("Error listing tags: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"tags": tagNames(tags)})
+	}
+}
+
+// ListCampaignsHandler liste les campagnes existantes (GET /api/v1/campaigns).
+func ListCampaignsHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		campaigns, err := linkService.ListCampaigns()
+		if err != nil {
+			log.Printf("Error listing campaigns: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		result := make([]gin.H, 0, len(campaigns))
+		for _, campaign := range campaigns {
+			result = append(result, gin.H{"name": campaign.Name, "created_at": campaign.CreatedAt})
+		}
+		c.JSON(http.StatusOK, gin.H{"campaigns": result})
+	}
+}
+
+// GetGroupStatsHandler retourne les statistiques agrégées d'une étiquette, d'une campagne ou d'un dossier
+// (GET /api/v1/stats?tag=promo&interval=day&days=30&top=10).
+func GetGroupStatsHandler(clickService *services.ClickService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		days, _ := strconv.Atoi(c.Query("days"))
+		top, _ := strconv.Atoi(c.Query("top"))
+
+		filter := repository.LinkFilter{
+			Tag:      c.Query("tag"),
+			Campaign: c.Query("campaign"),
+			Folder:   c.Query("folder"),
+		}
+		stats, err := clickService.GetGroupStats(services.GroupStatsQuery{
+			Filter:   filter,
+			Interval: c.Query("interval"),
+			Days:     days,
+			Top:      top,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidStatsQuery) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			log.Printf("Error computing group stats: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		filter = services.NormalizeLinkFilter(filter)
+		c.JSON(http.StatusOK, gin.H{
+			"tag":          filter.Tag,
+			"campaign":     filter.Campaign,
+			"folder":       filter.Folder,
+			"total_clicks": stats.TotalClicks,
+			"timeseries":   stats.Timeseries,
+			"top_links":    stats.TopLinks,
+		})
+	}
+}
+
+// tagNames retourne les noms des étiquettes.
+func tagNames(tags []models.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// campaignName retourne le nom de la campagne, ou une chaîne vide si le lien n'en a pas.
+func campaignName(campaign *models.Campaign) string {
+	if campaign == nil {
+		return ""
+	}
+	return campaign.Name
+}
@@ -2,12 +2,16 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/policy"
 	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/Quanghng/url-shortener/internal/useragent"
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,8 +19,17 @@ import (
 // aux workers asynchrones. Il est bufferisé pour ne pas bloquer les requêtes de redirection.
 var ClickEventsChannel chan models.ClickEvent
 
-// SetupRoutes configure toutes les routes de l'API Gin et injecte les dépendances nécessaires
-func SetupRoutes(router *gin.Engine, linkService *services.LinkService) {
+// RouteMiddlewares regroupe les middlewares appliqués à des routes spécifiques.
+type RouteMiddlewares struct {
+	AdminAuth       gin.HandlerFunc // Protège les routes d'administration (/api/v1/admin) et les listes de liens
+	PasswordLimiter gin.HandlerFunc // Limite les tentatives de mot de passe sur les liens protégés
+}
+
+// SetupRoutes configure toutes les routes de l'API Gin et injecte les dépendances nécessaires.
+func SetupRoutes(router *gin.Engine, linkService *services.LinkService, clickService *services.ClickService, reportService *services.ReportService, exportService *services.ExportService, auditService *services.AuditService, webhookService *services.WebhookService, mw RouteMiddlewares) {
+	// Pages HTML embarquées (avertissements, formulaires)
+	router.SetHTMLTemplate(loadTemplates())
+
 	// Route de Health Check
 	router.GET("/health", HealthCheckHandler)
 
@@ -24,11 +37,50 @@ func SetupRoutes(router *gin.Engine, linkService *services.LinkService) {
 	v1 := router.Group("/api/v1")
 	{
 		v1.POST("/links", CreateShortLinkHandler(linkService))
-		v1.GET("/links/:shortCode/stats", GetLinkStatsHandler(linkService))
+		v1.POST("/links/bulk", mw.AdminAuth, CreateBulkLinksHandler(linkService))
+		v1.GET("/links", mw.AdminAuth, ListLinksHandler(linkService))
+		v1.GET("/tags", mw.AdminAuth, ListTagsHandler(linkService))
+		v1.GET("/campaigns", mw.AdminAuth, ListCampaignsHandler(linkService))
+		v1.GET("/stats", mw.AdminAuth, GetGroupStatsHandler(clickService))
+		v1.GET("/export", mw.AdminAuth, ExportHandler(exportService))
+		v1.GET("/links/:shortCode/stats", GetLinkStatsHandler(linkService, clickService))
+		v1.GET("/links/:shortCode/health", GetLinkHealthHandler(linkService))
+		v1.GET("/links/:shortCode/qr", GetLinkQRCodeHandler(linkService))
+		v1.PATCH("/links/:shortCode", mw.AdminAuth, UpdateDestinationHandler(linkService))
+		v1.GET("/links/:shortCode/history", mw.AdminAuth, GetLinkHistoryHandler(linkService))
+		v1.POST("/links/:shortCode/rollback", mw.AdminAuth, RollbackDestinationHandler(linkService))
+		v1.DELETE("/links/:shortCode", mw.AdminAuth, DeleteLinkHandler(linkService))
+		v1.POST("/links/:shortCode/restore", mw.AdminAuth, RestoreLinkHandler(linkService))
+		v1.GET("/trash", mw.AdminAuth, ListTrashHandler(linkService))
+		v1.GET("/audit", mw.AdminAuth, ListAuditEntriesHandler(auditService))
+
+		// Abonnements webhook et journal de leurs livraisons
+		v1.POST("/webhooks", mw.AdminAuth, CreateWebhookHandler(webhookService))
+		v1.GET("/webhooks", mw.AdminAuth, ListWebhooksHandler(webhookService))
+		v1.DELETE("/webhooks/:id", mw.AdminAuth, DeleteWebhookHandler(webhookService))
+		v1.GET("/webhooks/:id/deliveries", mw.AdminAuth, ListDeliveriesHandler(webhookService))
+		v1.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", mw.AdminAuth, RedeliverHandler(webhookService))
+
+		// Routes d'administration (modération des signalements et des liens)
+		admin := v1.Group("/admin", mw.AdminAuth)
+		{
+			admin.GET("/reports", ListReportsHandler(reportService))
+			admin.GET("/reports/:id", GetReportHandler(reportService))
+			admin.POST("/reports/:id/actions", ApplyModerationActionHandler(reportService))
+			admin.GET("/banned-domains", ListBannedDomainsHandler(reportService))
+			admin.POST("/links/:shortCode/enable", EnableLinkHandler(linkService))
+		}
 	}
 
+	// Signalement d'abus d'un lien court
+	router.POST("/:shortCode/report", SubmitReportHandler(linkService, reportService))
+
 	// Route de Redirection (au niveau racine pour les short codes)
 	router.GET("/:shortCode", RedirectHandler(linkService))
+	// Aperçu d'un lien sans redirection (aussi accessible via "/:shortCode+")
+	router.GET("/:shortCode/preview", PreviewHandler(linkService))
+	// Soumission du mot de passe d'un lien protégé (tentatives limitées par IP et par lien)
+	router.POST("/:shortCode", mw.PasswordLimiter, PasswordRedirectHandler(linkService))
 }
 
 // HealthCheckHandler gère la route /health pour vérifier l'état du service.
@@ -38,7 +90,64 @@ func HealthCheckHandler(c *gin.Context) {
 
 // CreateLinkRequest représente le corps de la requête JSON pour la création d'un lien.
 type CreateLinkRequest struct {
-	LongURL string `json:"long_url" binding:"required,url"` // 'binding:required' pour validation, 'url' pour format URL
+	LongURL   string `json:"long_url" binding:"required,url"`           // 'binding:required' pour validation, 'url' pour format URL
+	Password  string `json:"password" binding:"omitempty,min=4,max=72"` // Mot de passe facultatif protégeant la redirection
+	MaxClicks int    `json:"max_clicks" binding:"min=0"`                // Nombre maximal de redirections (0 = illimité)
+
+	StartsAt    *time.Time       `json:"starts_at"`                            // Date d'activation (RFC 3339)
+	Schedule    *models.Schedule `json:"schedule"`                             // Plages horaires d'ouverture
+	FallbackURL string           `json:"fallback_url" binding:"omitempty,url"` // URL de repli en dehors de la période d'activation
+
+	DeviceRules []models.DeviceRule `json:"device_rules"` // Destinations alternatives par plateforme ou appareil
+	GeoRules    []models.GeoRule    `json:"geo_rules"`    // Destinations alternatives par pays
+	Variants    []models.Variant    `json:"variants"`     // Test A/B : destinations pondérées
+
+	UTM           *models.UTM `json:"utm"`            // Paramètres utm_* ajoutés à la destination
+	ForwardQuery  bool        `json:"forward_query"`  // Transmet les paramètres de requête du visiteur
+	QueryConflict string      `json:"query_conflict"` // keep (défaut), override ou append
+
+	Tags     []string `json:"tags"`     // Étiquettes du lien
+	Campaign string   `json:"campaign"` // Campagne du lien
+	Folder   string   `json:"folder"`   // Dossier du lien ("marketing/2025")
+
+	Domain string `json:"domain"` // Domaine personnalisé du lien (vide = domaine par défaut)
+
+	SocialCard models.SocialCard `json:"social_card"` // Balises Open Graph servies aux robots d'aperçu
+
+	Dedupe *bool `json:"dedupe"` // Réutilise un lien existant vers la même destination (absent = réglage dedupe.enabled)
+}
+
+// options convertit la requête en options de création du LinkService.
+func (req CreateLinkRequest) options() services.CreateLinkOptions {
+	return services.CreateLinkOptions{
+		Password:    req.Password,
+		MaxClicks:   req.MaxClicks,
+		StartsAt:    req.StartsAt,
+		Schedule:    req.Schedule,
+		FallbackURL: req.FallbackURL,
+		DeviceRules: req.DeviceRules,
+		GeoRules:    req.GeoRules,
+		Variants:    req.Variants,
+
+		UTM:           req.UTM,
+		ForwardQuery:  req.ForwardQuery,
+		QueryConflict: req.QueryConflict,
+
+		Tags:     req.Tags,
+		Campaign: req.Campaign,
+		Folder:   req.Folder,
+
+		Domain: req.Domain,
+
+		SocialCard: req.SocialCard,
+
+		Dedupe: req.Dedupe,
+	}
+}
+
+// anonymousActor identifie, dans le journal d'audit, l'auteur d'une action sans authentification (adresse IP).
+func anonymousActor(c *gin.Context) string {
+	return "anonymous:" + c.ClientIP()
 }
 
 // CreateShortLinkHandler gère la création d'une URL courte.
@@ -53,96 +162,416 @@ func CreateShortLinkHandler(linkService *services.LinkService) gin.HandlerFunc {
 		}
 
 		// Appeler le LinkService (CreateLink) pour créer le nouveau lien.
-		link, err := linkService.CreateLink(req.LongURL)
+		opts := req.options()
+		opts.Actor = anonymousActor(c)
+		link, created, err := linkService.CreateLink(req.LongURL, opts)
+		if isLinkValidationError(err) {
+			// Destination refusée ou paramètres invalides : erreur de validation explicite
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		if err != nil {
+			log.Printf("Error creating short link for %s: %v", req.LongURL, err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create short link"})
 			return
 		}
 
 		// Retourne le code court et l'URL longue dans la réponse JSON.
-		c.JSON(http.StatusCreated, gin.H{
-			"short_code":     link.ShortCode,
-			"long_url":       link.LongURL,
-			"full_short_url": "http://localhost:8080/" + link.ShortCode,
-		})
+		response := linkJSON(link)
+		response["full_short_url"] = linkService.ShortURL(link)
+		if !created {
+			// Déduplication : le lien existant vers la même destination est retourné
+			response["existing"] = true
+			c.JSON(http.StatusOK, response)
+			return
+		}
+		c.JSON(http.StatusCreated, response)
+	}
+}
+
+// isLinkValidationError indique si l'erreur provient de la validation des paramètres d'un lien
+// (destination refusée ou signalée, limite de clics ou calendrier invalide).
+func isLinkValidationError(err error) bool {
+	return errors.Is(err, policy.ErrDestinationNotAllowed) ||
+		errors.Is(err, services.ErrDestinationFlagged) ||
+		errors.Is(err, services.ErrInvalidMaxClicks) ||
+		errors.Is(err, services.ErrInvalidSchedule) ||
+		errors.Is(err, services.ErrInvalidRoutingRule) ||
+		errors.Is(err, services.ErrInvalidQueryOption) ||
+		errors.Is(err, services.ErrInvalidGrouping) ||
+		errors.Is(err, services.ErrInvalidDomain) ||
+		errors.Is(err, services.ErrInvalidSocialCard)
+}
+
+// linkJSON construit la représentation JSON d'un lien et de ses options.
+func linkJSON(link *models.Link) gin.H {
+	return gin.H{
+		"short_code":         link.ShortCode,
+		"domain":             link.Domain,
+		"long_url":           link.LongURL,
+		"created_at":         link.CreatedAt,
+		"password_protected": link.IsPasswordProtected(),
+		"max_clicks":         link.MaxClicks,
+		"starts_at":          link.StartsAt,
+		"schedule":           link.Schedule,
+		"fallback_url":       link.FallbackURL,
+		"device_rules":       link.DeviceRules,
+		"geo_rules":          link.GeoRules,
+		"variants":           link.Variants,
+		"utm":                link.UTM,
+		"forward_query":      link.ForwardQuery,
+		"query_conflict":     link.QueryConflict,
+		"tags":               tagNames(link.Tags),
+		"campaign":           campaignName(link.Campaign),
+		"folder":             link.Folder,
+		"metadata":           metadataJSON(link.Metadata),
+		"social_card":        link.SocialCard,
 	}
 }
 
+// metadataJSON construit la représentation JSON des métadonnées de la destination d'un lien
+// (fetched_at vaut null tant que la récupération asynchrone n'a pas eu lieu).
+func metadataJSON(meta models.LinkMetadata) gin.H {
+	result := gin.H{
+		"title":       meta.Title,
+		"description": meta.Description,
+		"favicon_url": meta.FaviconURL,
+		"image_url":   meta.ImageURL,
+		"fetched_at":  meta.FetchedAt,
+	}
+	if meta.Error != "" {
+		result["error"] = meta.Error
+	}
+	return result
+}
+
 // RedirectHandler gère la redirection d'une URL courte vers l'URL longue et l'enregistrement asynchrone des clics.
 func RedirectHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// "/xyz123+" : aperçu du lien au lieu de la redirection
+		if shortCode := c.Param("shortCode"); isPreviewRequest(shortCode) {
+			renderPreview(c, linkService, strings.TrimSuffix(shortCode, previewSuffix))
+			return
+		}
+
+		link, ok := loadRedirectLink(c, linkService)
+		if !ok {
+			return
+		}
+
+		// Robot d'aperçu (messagerie, réseau social) : page de balises Open Graph au lieu de la redirection.
+		// Ces visites ne comptent pas comme des clics.
+		if useragent.IsUnfurlBot(c.Request.UserAgent()) {
+			card := linkService.SocialCardFor(link)
+			c.HTML(http.StatusOK, "social.html", gin.H{
+				"ShortURL":    linkService.ShortURL(link),
+				"Title":       card.Title,
+				"Description": card.Description,
+				"ImageURL":    card.ImageURL,
+			})
+			return
+		}
+
+		// Lien protégé : affiche le formulaire de mot de passe, le clic ne sera compté qu'après vérification
+		if link.IsPasswordProtected() {
+			// Le formulaire est renvoyé sur la même URL afin de conserver les paramètres de requête
+			c.HTML(http.StatusOK, "password.html", gin.H{"ShortCode": link.ShortCode, "Action": c.Request.URL.RequestURI()})
+			return
+		}
+
+		redirectToDestination(c, linkService, link)
+	}
+}
+
+// PasswordRedirectHandler vérifie le mot de passe soumis pour un lien protégé,
+// puis effectue la redirection et enregistre le clic en cas de succès.
+func PasswordRedirectHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		link, ok := loadRedirectLink(c, linkService)
+		if !ok {
+			return
+		}
+
+		if err := linkService.VerifyPassword(link, c.PostForm("password")); err != nil {
+			c.HTML(http.StatusUnauthorized, "password.html", gin.H{
+				"ShortCode": link.ShortCode,
+				"Action":    c.Request.URL.RequestURI(),
+				"Error":     "Mot de passe incorrect.",
+			})
+			return
+		}
+
+		redirectToDestination(c, linkService, link)
+	}
+}
+
+// loadRedirectLink récupère le lien demandé et vérifie qu'il peut être suivi.
+// En cas d'échec, la réponse est déjà écrite et ok vaut false.
+func loadRedirectLink(c *gin.Context, linkService *services.LinkService) (*models.Link, bool) {
+	// Récupère le shortCode de l'URL avec c.Param
+	shortCode := c.Param("shortCode")
+
+	// Récupérer l'URL longue associée au shortCode sur le domaine de la requête (en-tête Host)
+	link, err := linkService.GetLinkByShortCode(linkService.DomainForHost(c.Request.Host), shortCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrShortCodeRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		case errors.Is(err, services.ErrLinkDeleted):
+			// Lien à la corbeille : il a existé mais ne redirige plus
+			c.JSON(http.StatusGone, gin.H{"error": "Short link has been deleted"})
+			return nil, false
+		case errors.Is(err, services.ErrLinkNotFound):
+			// Si le lien n'est pas trouvé, retourner HTTP 404 Not Found.
+			c.JSON(http.StatusNotFound, gin.H{"error": "Short link not found"})
+			return nil, false
+		}
+		// Gérer d'autres erreurs potentielles de la base de données ou du service
+		log.Printf("Error retrieving link for %s: %v", shortCode, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return nil, false
+	}
+
+	// Lien signalé comme malveillant : page d'avertissement au lieu de la redirection
+	if link.FlaggedAt != nil {
+		c.HTML(http.StatusForbidden, "warning.html", gin.H{
+			"ShortCode": link.ShortCode,
+			"LongURL":   link.LongURL,
+			"Reason":    link.FlagReason,
+		})
+		return nil, false
+	}
+
+	// Lien désactivé : il ne redirige plus
+	if link.IsDisabled {
+		c.JSON(http.StatusGone, gin.H{"error": "Short link has been disabled"})
+		return nil, false
+	}
+
+	// Lien à usage limité dont toutes les redirections ont été consommées
+	if link.IsExhausted() {
+		c.JSON(http.StatusGone, gin.H{"error": services.ErrLinkExhausted.Error()})
+		return nil, false
+	}
+
+	// Lien programmé : en dehors de sa période d'activation, redirection de repli ou page d'attente.
+	// Ces visites ne comptent pas comme des clics.
+	if !link.IsAvailableAt(time.Now()) {
+		if link.FallbackURL != "" {
+			c.Redirect(http.StatusFound, link.FallbackURL)
+			return nil, false
+		}
+		c.HTML(http.StatusForbidden, "unavailable.html", unavailablePage(link))
+		return nil, false
+	}
+
+	return link, true
+}
+
+// Cookie mémorisant la variante A/B attribuée à un visiteur, un cookie par lien.
+const (
+	variantCookiePrefix = "ab_"
+	variantCookieMaxAge = 30 * 24 * 60 * 60 // 30 jours, en secondes
+)
+
+// redirectToDestination consomme une redirection pour les liens limités, enregistre le clic
+// de manière asynchrone puis redirige vers l'URL longue.
+func redirectToDestination(c *gin.Context, linkService *services.LinkService, link *models.Link) {
+	// Réservation atomique : deux redirections concurrentes ne peuvent pas dépasser MaxClicks
+	if err := linkService.ConsumeClick(link); err != nil {
+		if errors.Is(err, services.ErrLinkExhausted) {
+			c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Error consuming click for %s: %v", link.ShortCode, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	// Choisir la destination selon le visiteur (règles de routage, variante A/B déjà attribuée)
+	cookieName := variantCookiePrefix + link.ShortCode
+	assigned, _ := c.Cookie(cookieName)
+	resolution := linkService.ResolveDestination(link, services.Visitor{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Variant:   assigned,
+		Query:     c.Request.URL.Query(),
+	})
+
+	// Mémorise la variante pour que le visiteur retrouve la même destination à chaque visite
+	if resolution.Variant != "" && resolution.Variant != assigned {
+		c.SetCookie(cookieName, resolution.Variant, variantCookieMaxAge, "/"+link.ShortCode, "", false, true)
+	}
+
+	// Créer un ClickEvent avec les informations pertinentes
+	clickEvent := models.ClickEvent{
+		LinkID:    link.ID,
+		ShortCode: link.ShortCode,
+		Domain:    link.Domain,
+		Timestamp: time.Now(),
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+		Country:   resolution.Country,
+		Platform:  resolution.Platform,
+		Device:    resolution.Device,
+		Variant:   resolution.Variant,
+		Source:    resolution.Source,
+	}
+
+	// Envoyer le ClickEvent dans le ClickEventsChannel avec le Multiplexage.
+	// Utilise un `select` avec un `default` pour éviter de bloquer si le channel est plein.
+	select {
+	case ClickEventsChannel <- clickEvent:
+		// Event envoyé avec succès
+	default:
+		log.Printf("Warning: ClickEventsChannel is full, dropping click event for %s.", link.ShortCode)
+	}
+
+	// Effectuer la redirection HTTP 302 (StatusFound) vers la destination retenue
+	c.Redirect(http.StatusFound, resolution.URL)
+}
+
+// GetLinkStatsHandler gère la récupération des statistiques pour un lien spécifique.
+func GetLinkStatsHandler(linkService *services.LinkService, clickService *services.ClickService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Récupère le shortCode de l'URL avec c.Param
 		shortCode := c.Param("shortCode")
 
-		// Récupérer l'URL longue associée au shortCode depuis le linkService
-		link, err := linkService.GetLinkByShortCode(shortCode)
+		// Appeler le LinkService pour obtenir le lien et le nombre total de clics
+		stats, err := linkService.GetLinkStats(c.Query("domain"), shortCode)
 		if err != nil {
 			switch {
-			case errors.Is(err, services.ErrShortCodeRequired):
+			case errors.Is(err, services.ErrShortCodeRequired), errors.Is(err, services.ErrInvalidDomain):
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
 			case errors.Is(err, services.ErrLinkNotFound):
-				// Si le lien n'est pas trouvé, retourner HTTP 404 Not Found.
+				// Gérer le cas où le lien n'est pas trouvé
 				c.JSON(http.StatusNotFound, gin.H{"error": "Short link not found"})
 				return
 			}
-			// Gérer d'autres erreurs potentielles de la base de données ou du service
-			log.Printf("Error retrieving link for %s: %v", shortCode, err)
+			// Gérer d'autres erreurs
+			log.Printf("Error retrieving stats for %s: %v", shortCode, err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 			return
 		}
 
-		// Créer un ClickEvent avec les informations pertinentes
-		clickEvent := models.ClickEvent{
-			LinkID:    link.ID,
-			Timestamp: time.Now(),
-			UserAgent: c.Request.UserAgent(),
-			IPAddress: c.ClientIP(),
+		link := stats.Link
+
+		// Retourne les statistiques dans la réponse JSON.
+		response := gin.H{
+			"short_code":   link.ShortCode,
+			"long_url":     link.LongURL,
+			"total_clicks": stats.TotalClicks,
+			"health":       linkHealth(link),
+		}
+
+		// Clics par variante du test A/B
+		variants, err := clickService.GetVariantStats(link)
+		if err != nil {
+			log.Printf("Error retrieving variant stats for %s: %v", shortCode, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+		if len(variants) > 0 {
+			response["variants"] = variants
 		}
 
-		// Envoyer le ClickEvent dans le ClickEventsChannel avec le Multiplexage.
-		// Utilise un `select` avec un `default` pour éviter de bloquer si le channel est plein.
-		select {
-		case ClickEventsChannel <- clickEvent:
-			// Event envoyé avec succès
-		default:
-			log.Printf("Warning: ClickEventsChannel is full, dropping click event for %s.", shortCode)
+		// Répartitions des clics par pays, plateforme, appareil et provenance
+		for _, dimension := range []string{"country", "platform", "device", "source"} {
+			breakdown, err := clickService.GetClicksBreakdown(link.ID, dimension)
+			if err != nil {
+				log.Printf("Error retrieving click breakdown by %s for %s: %v", dimension, shortCode, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+				return
+			}
+			response["clicks_by_"+dimension] = breakdown
 		}
 
-		// Effectuer la redirection HTTP 302 (StatusFound) vers l'URL longue
-		c.Redirect(http.StatusFound, link.LongURL)
+		c.JSON(http.StatusOK, response)
 	}
 }
 
-// GetLinkStatsHandler gère la récupération des statistiques pour un lien spécifique.
-func GetLinkStatsHandler(linkService *services.LinkService) gin.HandlerFunc {
+// GetLinkHealthHandler retourne l'état de santé d'un lien tel que mesuré par le moniteur
+// (accessibilité et vérification du certificat TLS pour les destinations HTTPS).
+func GetLinkHealthHandler(linkService *services.LinkService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Récupère le shortCode de l'URL avec c.Param
 		shortCode := c.Param("shortCode")
 
-		// Appeler le LinkService pour obtenir le lien et le nombre total de clics
-		link, totalClicks, err := linkService.GetLinkStats(shortCode)
+		link, err := linkService.GetLinkByShortCode(c.Query("domain"), shortCode)
 		if err != nil {
 			switch {
-			case errors.Is(err, services.ErrShortCodeRequired):
+			case errors.Is(err, services.ErrShortCodeRequired), errors.Is(err, services.ErrInvalidDomain):
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
 			case errors.Is(err, services.ErrLinkNotFound):
-				// Gérer le cas où le lien n'est pas trouvé
 				c.JSON(http.StatusNotFound, gin.H{"error": "Short link not found"})
 				return
 			}
-			// Gérer d'autres erreurs
-			log.Printf("Error retrieving stats for %s: %v", shortCode, err)
+			log.Printf("Error retrieving health for %s: %v", shortCode, err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 			return
 		}
 
-		// Retourne les statistiques dans la réponse JSON.
-		c.JSON(http.StatusOK, gin.H{
-			"short_code":   link.ShortCode,
-			"long_url":     link.LongURL,
-			"total_clicks": totalClicks,
-		})
+		health := linkHealth(link)
+		health["short_code"] = link.ShortCode
+		health["long_url"] = link.LongURL
+		c.JSON(http.StatusOK, health)
+	}
+}
+
+// linkHealth construit la représentation JSON de l'état de santé d'un lien.
+func linkHealth(link *models.Link) gin.H {
+	tlsInfo := gin.H{
+		"status":     link.TLSStatus,
+		"error":      link.TLSError,
+		"expires_at": link.TLSExpiresAt,
+		"checked_at": link.TLSCheckedAt,
+		"chain":      link.TLSChain,
+	}
+	return gin.H{
+		"is_active":          link.IsActive,
+		"is_disabled":        link.IsDisabled,
+		"password_protected": link.IsPasswordProtected(),
+		"max_clicks":         link.MaxClicks,
+		"remaining_clicks":   remainingClicks(link),
+		"tls":                tlsInfo,
+	}
+}
+
+// remainingClicks retourne le nombre de redirections restantes d'un lien limité, nil s'il est illimité.
+func remainingClicks(link *models.Link) *int {
+	if link.MaxClicks <= 0 {
+		return nil
+	}
+	remaining := link.MaxClicks - link.ClickCount
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining
+}
+
+// unavailablePage prépare les données de la page "pas encore disponible",
+// avec les dates et horaires exprimés dans le fuseau horaire du lien.
+func unavailablePage(link *models.Link) gin.H {
+	loc := time.UTC
+	var windows []string
+	if link.Schedule != nil {
+		if l, err := link.Schedule.Location(); err == nil {
+			loc = l
+		}
+		for _, w := range link.Schedule.Windows {
+			days := "tous les jours"
+			if len(w.Days) > 0 {
+				days = strings.Join(w.Days, ", ")
+			}
+			windows = append(windows, fmt.Sprintf("%s : %s - %s", days, w.Start, w.End))
+		}
+	}
+
+	data := gin.H{"Timezone": loc.String(), "Windows": windows}
+	if link.StartsAt != nil && time.Now().Before(*link.StartsAt) {
+		data["StartsAt"] = link.StartsAt.In(loc).Format("02/01/2006 15:04 MST")
 	}
+	return data
 }
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// previewSuffix, ajouté à un code court ("/xyz123+"), affiche l'aperçu du lien au lieu de rediriger.
+const previewSuffix = "+"
+
+// PreviewHandler affiche la page d'aperçu d'un lien (GET /:shortCode/preview).
+func PreviewHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		renderPreview(c, linkService, c.Param("shortCode"))
+	}
+}
+
+// renderPreview affiche la destination, la date de création, l'état et le nombre de clics d'un lien.
+// L'aperçu n'est pas une redirection : aucun ClickEvent n'est émis et aucune redirection n'est consommée.
+func renderPreview(c *gin.Context, linkService *services.LinkService, shortCode string) {
+	stats, err := linkService.GetLinkStats(linkService.DomainForHost(c.Request.Host), shortCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrShortCodeRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrLinkDeleted):
+			c.JSON(http.StatusGone, gin.H{"error": "Short link has been deleted"})
+		case errors.Is(err, services.ErrLinkNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Short link not found"})
+		default:
+			log.Printf("Error retrieving preview for %s: %v", shortCode, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	link := stats.Link
+	status, available := previewStatus(link)
+	c.HTML(http.StatusOK, "preview.html", gin.H{
+		"ShortCode":    link.ShortCode,
+		"ShortURL":     linkService.ShortURL(link),
+		"Destination":  link.LongURL,
+		"Protected":    link.IsPasswordProtected(),
+		"Routed":       len(link.DeviceRules) > 0 || len(link.GeoRules) > 0 || len(link.Variants) > 0,
+		"CreatedAt":    link.CreatedAt.Format("02/01/2006 15:04 MST"),
+		"Status":       status,
+		"Available":    available,
+		"Flagged":      link.FlaggedAt != nil,
+		"FlagReason":   link.FlagReason,
+		"Health":       previewHealth(link),
+		"Clicks":       stats.TotalClicks,
+		"RedirectPath": "/" + link.ShortCode,
+	})
+}
+
+// previewStatus décrit l'état du lien et indique s'il redirige actuellement.
+func previewStatus(link *models.Link) (string, bool) {
+	switch {
+	case link.FlaggedAt != nil:
+		return "Bloqué : destination signalée comme malveillante", false
+	case link.IsDisabled:
+		if link.DisabledReason != "" {
+			return "Désactivé : " + link.DisabledReason, false
+		}
+		return "Désactivé", false
+	case link.IsExhausted():
+		return fmt.Sprintf("Expiré : %d/%d redirections consommées", link.ClickCount, link.MaxClicks), false
+	case !link.IsAvailableAt(time.Now()):
+		return "Pas encore disponible (activation programmée)", false
+	case link.IsPasswordProtected():
+		return "Actif, protégé par un mot de passe", true
+	}
+	return "Actif", true
+}
+
+// previewHealth résume la dernière vérification du moniteur (accessibilité et certificat TLS).
+func previewHealth(link *models.Link) []string {
+	health := []string{"Destination inaccessible lors de la dernière vérification"}
+	if link.IsActive {
+		health[0] = "Destination accessible"
+	}
+	if link.TLSStatus != "" {
+		tls := "Certificat TLS : " + link.TLSStatus
+		if link.TLSExpiresAt != nil {
+			tls += " (expire le " + link.TLSExpiresAt.Format("02/01/2006") + ")"
+		}
+		if link.TLSCheckedAt != nil {
+			tls += ", vérifié le " + link.TLSCheckedAt.Format("02/01/2006 15:04 MST")
+		}
+		health = append(health, tls)
+	}
+	return health
+}
+
+// isPreviewRequest indique si le code demandé se termine par le suffixe d'aperçu ("xyz123+").
+func isPreviewRequest(shortCode string) bool {
+	return len(shortCode) > len(previewSuffix) && strings.HasSuffix(shortCode, previewSuffix)
+}
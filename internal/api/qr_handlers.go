@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Quanghng/url-shortener/internal/qr"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// GetLinkQRCodeHandler retourne le QR code de l'URL courte d'un lien
+// (GET /api/v1/links/:shortCode/qr?format=png|svg&size=256&margin=4&ecc=M&domain=go.acme.io).
+func GetLinkQRCodeHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		opts := qr.Options{Format: c.Query("format"), ECC: c.Query("ecc")}
+		if value := c.Query("size"); value != "" {
+			size, err := strconv.Atoi(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "size must be an integer"})
+				return
+			}
+			opts.Size = size
+		}
+		if value := c.Query("margin"); value != "" {
+			margin, err := strconv.Atoi(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "margin must be an integer"})
+				return
+			}
+			opts.Margin = &margin
+		}
+
+		code, err := linkService.GenerateQRCode(c.Query("domain"), c.Param("shortCode"), opts)
+		if err != nil {
+			switch {
+			case errors.Is(err, qr.ErrInvalidOptions), errors.Is(err, services.ErrShortCodeRequired), errors.Is(err, services.ErrInvalidDomain):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case errors.Is(err, services.ErrLinkNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Short link not found"})
+			default:
+				log.Printf("Error generating QR code for %s: %v", c.Param("shortCode"), err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			}
+			return
+		}
+
+		c.Header("X-QR-Content", code.URL)
+		c.Data(http.StatusOK, code.ContentType, code.Image)
+	}
+}
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Quanghng/url-shortener/internal/middleware"
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ReportRequest représente le corps de la requête JSON de signalement d'abus.
+type ReportRequest struct {
+	Reason  string `json:"reason" binding:"required"`               // Catégorie : phishing, malware, spam, illegal, other
+	Details string `json:"details" binding:"max=2000"`              // Description libre
+	Email   string `json:"email" binding:"omitempty,email,max=255"` // Contact facultatif
+}
+
+// ModerationActionRequest représente le corps de la requête JSON d'action de modération.
+type ModerationActionRequest struct {
+	Action string `json:"action" binding:"required"` // disable_link, ban_domain ou dismiss
+	Note   string `json:"note"`                      // Commentaire facultatif
+}
+
+// EnableLinkRequest représente le corps facultatif de la requête JSON de réactivation d'un lien.
+type EnableLinkRequest struct {
+	Note string `json:"note"` // Motif facultatif (journal d'audit)
+}
+
+// SubmitReportHandler gère le signalement d'abus d'un lien court (POST /:shortCode/report).
+func SubmitReportHandler(linkService *services.LinkService, reportService *services.ReportService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ReportRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := reportService.SubmitReport(linkService.DomainForHost(c.Request.Host), c.Param("shortCode"), req.Reason, req.Details, req.Email, c.ClientIP())
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrShortCodeRequired), errors.Is(err, services.ErrInvalidReportReason):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			case errors.Is(err, services.ErrLinkNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Short link not found"})
+				return
+			}
+			log.Printf("Error saving report for %s: %v", c.Param("shortCode"), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"id":      report.ID,
+			"status":  report.Status,
+			"message": "Report received, thank you",
+		})
+	}
+}
+
+// ListReportsHandler liste les signalements (GET /api/v1/admin/reports?status=open).
+func ListReportsHandler(reportService *services.ReportService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reports, err := reportService.ListReports(c.Query("status"))
+		if err != nil {
+			log.Printf("Error listing reports: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		result := make([]gin.H, 0, len(reports))
+		for i := range reports {
+			result = append(result, reportJSON(&reports[i]))
+		}
+		c.JSON(http.StatusOK, gin.H{"reports": result})
+	}
+}
+
+// GetReportHandler retourne un signalement et l'historique de ses actions (GET /api/v1/admin/reports/:id).
+func GetReportHandler(reportService *services.ReportService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := reportIDParam(c)
+		if !ok {
+			return
+		}
+
+		report, actions, err := reportService.GetReport(id)
+		if err != nil {
+			if errors.Is(err, services.ErrReportNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			log.Printf("Error retrieving report %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		result := reportJSON(report)
+		result["actions"] = moderationActionsJSON(actions)
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// ApplyModerationActionHandler applique une action à un signalement (POST /api/v1/admin/reports/:id/actions).
+func ApplyModerationActionHandler(reportService *services.ReportService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := reportIDParam(c)
+		if !ok {
+			return
+		}
+
+		var req ModerationActionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := reportService.ApplyAction(id, req.Action, c.GetString(middleware.ActorKey), req.Note)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrReportNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			case errors.Is(err, services.ErrInvalidModerationAction):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			case errors.Is(err, services.ErrLinkNotFound):
+				c.JSON(http.StatusConflict, gin.H{"error": "Reported link no longer exists (deleted)"})
+				return
+			}
+			log.Printf("Error applying moderation action on report %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, reportJSON(report))
+	}
+}
+
+// EnableLinkHandler réactive un lien désactivé ou signalé (POST /api/v1/admin/links/:shortCode/enable?domain=go.acme.io).
+// Le corps JSON est facultatif ; l'auteur de la réactivation est celui du jeton d'administration.
+func EnableLinkHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req EnableLinkRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		link, err := linkService.EnableLink(c.Query("domain"), c.Param("shortCode"), c.GetString(middleware.ActorKey), req.Note)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrShortCodeRequired), errors.Is(err, services.ErrInvalidDomain):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			case errors.Is(err, services.ErrLinkNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Short link not found"})
+				return
+			case errors.Is(err, services.ErrLinkNotDisabled):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			log.Printf("Error enabling link %s: %v", c.Param("shortCode"), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		response := linkJSON(link)
+		response["full_short_url"] = linkService.ShortURL(link)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// ListBannedDomainsHandler liste les domaines bannis (GET /api/v1/admin/banned-domains).
+func ListBannedDomainsHandler(reportService *services.ReportService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		domains, err := reportService.ListBannedDomains()
+		if err != nil {
+			log.Printf("Error listing banned domains: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		result := make([]gin.H, 0, len(domains))
+		for _, d := range domains {
+			result = append(result, gin.H{
+				"domain":     d.Domain,
+				"reason":     d.Reason,
+				"actor":      d.Actor,
+				"created_at": d.CreatedAt,
+			})
+		}
+		c.JSON(http.StatusOK, gin.H{"banned_domains": result})
+	}
+}
+
+// reportIDParam lit le paramètre :id et répond 400 s'il est invalide.
+func reportIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// reportJSON construit la représentation JSON d'un signalement.
+func reportJSON(report *models.Report) gin.H {
+	return gin.H{
+		"id":             report.ID,
+		"short_code":     report.Link.ShortCode,
+		"long_url":       report.Link.LongURL,
+		"link_disabled":  report.Link.IsDisabled,
+		"reason":         report.Reason,
+		"details":        report.Details,
+		"reporter_email": report.ReporterEmail,
+		"reporter_ip":    report.ReporterIP,
+		"status":         report.Status,
+		"created_at":     report.CreatedAt,
+		"resolved_at":    report.ResolvedAt,
+	}
+}
+
+// moderationActionsJSON construit la représentation JSON de l'historique de modération.
+func moderationActionsJSON(actions []models.ModerationAction) []gin.H {
+	result := make([]gin.H, 0, len(actions))
+	for _, a := range actions {
+		result = append(result, gin.H{
+			"action":     a.Action,
+			"actor":      a.Actor,
+			"note":       a.Note,
+			"created_at": a.CreatedAt,
+		})
+	}
+	return result
+}
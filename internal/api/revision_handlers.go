@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Quanghng/url-shortener/internal/middleware"
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateDestinationRequest est le corps de PATCH /api/v1/links/:shortCode.
+type UpdateDestinationRequest struct {
+	LongURL string `json:"long_url" binding:"required,url"` // Nouvelle destination
+	Note    string `json:"note"`                            // Motif de la modification (historique)
+}
+
+// RollbackRequest est le corps de POST /api/v1/links/:shortCode/rollback.
+type RollbackRequest struct {
+	Revision *int   `json:"revision" binding:"required,min=0"` // Révision à rétablir (0 = destination d'origine)
+	Note     string `json:"note"`                              // Motif du retour arrière
+}
+
+// UpdateDestinationHandler modifie la destination d'un lien (PATCH /api/v1/links/:shortCode?domain=go.acme.io).
+// L'auteur de la modification est lu dans l'en-tête X-Admin-Actor.
+func UpdateDestinationHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req UpdateDestinationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		link, rev, err := linkService.UpdateDestination(c.Query("domain"), c.Param("shortCode"), req.LongURL, c.GetString(middleware.ActorKey), req.Note)
+		if err != nil {
+			writeRevisionError(c, err)
+			return
+		}
+		response := linkJSON(link)
+		response["full_short_url"] = linkService.ShortURL(link)
+		response["revision"] = revisionJSON(*rev)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// RollbackDestinationHandler rétablit la destination d'une révision précédente
+// (POST /api/v1/links/:shortCode/rollback?domain=go.acme.io).
+func RollbackDestinationHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RollbackRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		link, rev, err := linkService.RollbackDestination(c.Query("domain"), c.Param("shortCode"), *req.Revision, c.GetString(middleware.ActorKey), req.Note)
+		if err != nil {
+			writeRevisionError(c, err)
+			return
+		}
+		response := linkJSON(link)
+		response["full_short_url"] = linkService.ShortURL(link)
+		response["revision"] = revisionJSON(*rev)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetLinkHistoryHandler retourne l'historique des destinations d'un lien
+// (GET /api/v1/links/:shortCode/history?domain=go.acme.io).
+func GetLinkHistoryHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		history, err := linkService.GetLinkHistory(c.Query("domain"), c.Param("shortCode"))
+		if err != nil {
+			writeRevisionError(c, err)
+			return
+		}
+
+		revisions := make([]gin.H, 0, len(history.Revisions))
+		for _, rev := range history.Revisions {
+			revisions = append(revisions, revisionJSON(rev))
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"short_code":   history.Link.ShortCode,
+			"domain":       history.Link.Domain,
+			"created_at":   history.Link.CreatedAt,
+			"original_url": history.OriginalURL,
+			"long_url":     history.Link.LongURL,
+			"revisions":    revisions,
+		})
+	}
+}
+
+// revisionJSON construit la représentation JSON d'une révision de destination.
+func revisionJSON(rev models.LinkRevision) gin.H {
+	return gin.H{
+		"revision":    rev.Revision,
+		"old_url":     rev.OldURL,
+		"new_url":     rev.NewURL,
+		"actor":       rev.Actor,
+		"note":        rev.Note,
+		"rollback_of": rev.RollbackOf,
+		"created_at":  rev.CreatedAt,
+	}
+}
+
+// writeRevisionError traduit les erreurs de modification et d'historique en réponses HTTP.
+func writeRevisionError(c *gin.Context, err error) {
+	switch {
+	case isLinkValidationError(err), errors.Is(err, services.ErrShortCodeRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrLinkNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Short link not found"})
+	case errors.Is(err, services.ErrRevisionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrDestinationUnchanged):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Error updating destination of %s: %v", c.Param("shortCode"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"embed"
+	"html/template"
+)
+
+// templatesFS embarque les pages HTML servies par le serveur (avertissements, formulaires...).
+//
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+// loadTemplates analyse les templates HTML embarqués dans le binaire.
+func loadTemplates() *template.Template {
+	return template.Must(template.ParseFS(templatesFS, "templates/*.html"))
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Quanghng/url-shortener/internal/middleware"
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteLinkHandler met un lien à la corbeille (DELETE /api/v1/links/:shortCode?domain=go.acme.io).
+// L'auteur de la suppression est lu dans l'en-tête X-Admin-Actor.
+func DeleteLinkHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		link, err := linkService.DeleteLink(c.Query("domain"), c.Param("shortCode"), c.GetString(middleware.ActorKey))
+		if err != nil {
+			writeTrashError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, trashJSON(linkService, link))
+	}
+}
+
+// RestoreLinkHandler sort un lien de la corbeille (POST /api/v1/links/:shortCode/restore?domain=go.acme.io).
+// L'auteur de la restauration est lu dans l'en-tête X-Admin-Actor.
+func RestoreLinkHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		link, err := linkService.RestoreLink(c.Query("domain"), c.Param("shortCode"), c.GetString(middleware.ActorKey))
+		if err != nil {
+			writeTrashError(c, err)
+			return
+		}
+		response := linkJSON(link)
+		response["full_short_url"] = linkService.ShortURL(link)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// ListTrashHandler liste les liens à la corbeille (GET /api/v1/trash?tag=...&campaign=...&folder=...&limit=50&offset=0).
+func ListTrashHandler(linkService *services.LinkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.Query("limit"))
+		offset, _ := strconv.Atoi(c.Query("offset"))
+
+		links, err := linkService.ListTrash(repository.LinkFilter{
+			Tag:      c.Query("tag"),
+			Campaign: c.Query("campaign"),
+			Folder:   c.Query("folder"),
+			Limit:    limit,
+			Offset:   offset,
+		})
+		if err != nil {
+			log.Printf("Error listing trash: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		result := make([]gin.H, 0, len(links))
+		for i := range links {
+			result = append(result, trashJSON(linkService, &links[i]))
+		}
+		c.JSON(http.StatusOK, gin.H{"links": result, "count": len(result)})
+	}
+}
+
+// trashJSON construit la représentation JSON d'un lien à la corbeille : date et auteur
+// de la suppression, date de purge définitive (null si la purge automatique est désactivée).
+func trashJSON(linkService *services.LinkService, link *models.Link) gin.H {
+	response := linkJSON(link)
+	response["deleted_at"] = link.DeletedAt.Time
+	response["deleted_by"] = link.DeletedBy
+	response["purge_at"] = linkService.PurgeDate(link)
+	return response
+}
+
+// writeTrashError traduit les erreurs de suppression et de restauration en réponses HTTP.
+func writeTrashError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrShortCodeRequired), errors.Is(err, services.ErrInvalidDomain):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrLinkDeleted):
+		c.JSON(http.StatusConflict, gin.H{"error": "Short link is already in the trash"})
+	case errors.Is(err, services.ErrLinkNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Short link not found"})
+	case errors.Is(err, services.ErrNotInTrash):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Error updating trash for %s: %v", c.Param("shortCode"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
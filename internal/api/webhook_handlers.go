@@ -0,0 +1,188 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Quanghng/url-shortener/internal/middleware"
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateWebhookRequest est le corps de la création d'un abonnement webhook.
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events" binding:"required"`
+	Secret      string   `json:"secret"`
+	Description string   `json:"description"`
+}
+
+// CreateWebhookHandler crée un abonnement webhook (POST /api/v1/webhooks).
+// Le secret de signature n'est retourné en clair qu'à la création.
+func CreateWebhookHandler(webhookService *services.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateWebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		sub, err := webhookService.CreateSubscription(services.WebhookInput{
+			URL:         req.URL,
+			Secret:      req.Secret,
+			Events:      req.Events,
+			Description: req.Description,
+		}, c.GetString(middleware.ActorKey))
+		if err != nil {
+			writeWebhookError(c, err)
+			return
+		}
+		response := webhookJSON(sub)
+		response["secret"] = sub.Secret
+		c.JSON(http.StatusCreated, response)
+	}
+}
+
+// ListWebhooksHandler liste les abonnements webhook (GET /api/v1/webhooks).
+func ListWebhooksHandler(webhookService *services.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subs, err := webhookService.ListSubscriptions()
+		if err != nil {
+			log.Printf("Error listing webhooks: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		result := make([]gin.H, 0, len(subs))
+		for i := range subs {
+			result = append(result, webhookJSON(&subs[i]))
+		}
+		c.JSON(http.StatusOK, gin.H{"webhooks": result, "count": len(result)})
+	}
+}
+
+// DeleteWebhookHandler supprime un abonnement webhook (DELETE /api/v1/webhooks/:id).
+func DeleteWebhookHandler(webhookService *services.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := idParam(c, "id", "invalid webhook id")
+		if !ok {
+			return
+		}
+		if err := webhookService.DeleteSubscription(id, c.GetString(middleware.ActorKey)); err != nil {
+			writeWebhookError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// ListDeliveriesHandler consulte le journal des livraisons d'un abonnement
+// (GET /api/v1/webhooks/:id/deliveries?status=failed&limit=50&offset=0).
+func ListDeliveriesHandler(webhookService *services.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := idParam(c, "id", "invalid webhook id")
+		if !ok {
+			return
+		}
+		limit, _ := strconv.Atoi(c.Query("limit"))
+		offset, _ := strconv.Atoi(c.Query("offset"))
+
+		deliveries, err := webhookService.ListDeliveries(id, services.DeliveryQuery{
+			Status: c.Query("status"),
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			writeWebhookError(c, err)
+			return
+		}
+
+		result := make([]gin.H, 0, len(deliveries))
+		for i := range deliveries {
+			result = append(result, deliveryJSON(&deliveries[i]))
+		}
+		c.JSON(http.StatusOK, gin.H{"deliveries": result, "count": len(result)})
+	}
+}
+
+// RedeliverHandler programme une nouvelle livraison d'un événement
+// (POST /api/v1/webhooks/:id/deliveries/:deliveryId/redeliver).
+func RedeliverHandler(webhookService *services.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := idParam(c, "id", "invalid webhook id")
+		if !ok {
+			return
+		}
+		deliveryID, ok := idParam(c, "deliveryId", "invalid delivery id")
+		if !ok {
+			return
+		}
+
+		delivery, err := webhookService.Redeliver(id, deliveryID, c.GetString(middleware.ActorKey))
+		if err != nil {
+			writeWebhookError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, deliveryJSON(delivery))
+	}
+}
+
+// writeWebhookError traduit les erreurs des webhooks en réponses HTTP.
+func writeWebhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWebhook), errors.Is(err, services.ErrInvalidDeliveryQuery):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrWebhookNotFound), errors.Is(err, services.ErrDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrWebhooksDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Error handling webhook request: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
+
+// idParam lit un paramètre d'identifiant numérique et répond 400 s'il est invalide.
+func idParam(c *gin.Context, name, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// webhookJSON construit la représentation JSON d'un abonnement, secret masqué.
+func webhookJSON(sub *models.WebhookSubscription) gin.H {
+	return gin.H{
+		"id":          sub.ID,
+		"url":         sub.URL,
+		"events":      sub.Events,
+		"description": sub.Description,
+		"secret_hint": services.MaskSecret(sub.Secret),
+		"created_at":  sub.CreatedAt,
+	}
+}
+
+// deliveryJSON construit la représentation JSON d'une livraison.
+func deliveryJSON(delivery *models.WebhookDelivery) gin.H {
+	return gin.H{
+		"id":               delivery.ID,
+		"subscription_id":  delivery.SubscriptionID,
+		"event_id":         delivery.EventID,
+		"event":            delivery.Event,
+		"payload":          json.RawMessage(delivery.Payload),
+		"status":           delivery.Status,
+		"attempts":         delivery.Attempts,
+		"last_status_code": delivery.LastStatusCode,
+		"last_error":       delivery.LastError,
+		"next_attempt_at":  delivery.NextAttemptAt,
+		"delivered_at":     delivery.DeliveredAt,
+		"redelivery_of":    delivery.RedeliveryOf,
+		"created_at":       delivery.CreatedAt,
+	}
+}
@@ -10,17 +10,29 @@ import (
 // Les tags `mapstructure` sont utilisés par Viper pour mapper les clés du fichier de config
 // (ou des variables d'environnement) aux champs de la structure Go.
 type Config struct {
-	Server    ServerConfig    `mapstructure:"server"`    // Configuration du serveur HTTP
-	Database  DatabaseConfig  `mapstructure:"database"`  // Configuration de la base de données
-	Analytics AnalyticsConfig `mapstructure:"analytics"` // Configuration pour l'enregistrement des clics
-	Monitor   MonitorConfig   `mapstructure:"monitor"`   // Configuration du moniteur d'URLs
+	Server     ServerConfig     `mapstructure:"server"`     // Configuration du serveur HTTP
+	Database   DatabaseConfig   `mapstructure:"database"`   // Configuration de la base de données
+	Analytics  AnalyticsConfig  `mapstructure:"analytics"`  // Configuration pour l'enregistrement des clics
+	Monitor    MonitorConfig    `mapstructure:"monitor"`    // Configuration du moniteur d'URLs
+	Security   SecurityConfig   `mapstructure:"security"`   // Politique de sécurité des URLs de destination
+	Reputation ReputationConfig `mapstructure:"reputation"` // Listes de blocage des URLs malveillantes
+	GeoIP      GeoIPConfig      `mapstructure:"geoip"`      // Base GeoIP locale pour le routage par pays
+	Metadata   MetadataConfig   `mapstructure:"metadata"`   // Récupération des métadonnées des pages de destination
+	ShortCode  ShortCodeConfig  `mapstructure:"shortcode"`  // Stratégie de génération des codes courts
+	Dedupe     DedupeConfig     `mapstructure:"dedupe"`     // Déduplication des liens vers une même destination
+	Trash      TrashConfig      `mapstructure:"trash"`      // Corbeille des liens supprimés
+	Webhooks   WebhooksConfig   `mapstructure:"webhooks"`   // Envoi des événements aux webhooks abonnés
 }
 
 // ServerConfig contient les paramètres du serveur web
 type ServerConfig struct {
-	Port      int             `mapstructure:"port"`       // Port d'écoute du serveur (ex: 8080)
-	BaseURL   string          `mapstructure:"base_url"`   // URL de base pour la génération des URLs courtes complètes
-	RateLimit RateLimitConfig `mapstructure:"rate_limit"` // Paramètres de limitation de débit
+	Port       int             `mapstructure:"port"`        // Port d'écoute du serveur (ex: 8080)
+	BaseURL    string          `mapstructure:"base_url"`    // URL de base pour la génération des URLs courtes complètes
+	Domains    []string        `mapstructure:"domains"`     // Domaines personnalisés supplémentaires (URLs de base, ex: "https://go.acme.io")
+	RateLimit  RateLimitConfig `mapstructure:"rate_limit"`  // Paramètres de limitation de débit
+	AdminToken string          `mapstructure:"admin_token"` // Jeton partagé des routes d'administration (auteur "admin", nom déclaré non vérifié)
+
+	AdminTokens map[string]string `mapstructure:"admin_tokens"` // Jetons personnels : nom de l'administrateur -> jeton (auteur établi par le serveur)
 }
 
 // RateLimitConfig définit les paramètres de limitation de débit côté serveur.
@@ -41,7 +53,79 @@ type AnalyticsConfig struct {
 
 // MonitorConfig contient les paramètres pour le moniteur d'URLs
 type MonitorConfig struct {
-	IntervalMinutes int `mapstructure:"interval_minutes"` // Intervalle en minutes entre chaque vérification d'URLs (ex: 5)
+	IntervalMinutes      int `mapstructure:"interval_minutes"`        // Intervalle en minutes entre chaque vérification d'URLs (ex: 5)
+	TLSExpiryWarningDays int `mapstructure:"tls_expiry_warning_days"` // Nombre de jours avant expiration d'un certificat déclenchant une alerte (ex: 14)
+}
+
+// SecurityConfig définit la politique appliquée aux URLs de destination (protection SSRF).
+// Elle est vérifiée à la création des liens et à chaque connexion sortante (moniteur).
+type SecurityConfig struct {
+	AllowedSchemes       []string        `mapstructure:"allowed_schemes"`        // Schémas acceptés (ex: http, https)
+	BlockPrivateNetworks bool            `mapstructure:"block_private_networks"` // Refuse les adresses privées, loopback et link-local
+	AllowedDomains       []string        `mapstructure:"allowed_domains"`        // Si non vide, seuls ces domaines (et sous-domaines) sont acceptés
+	DeniedDomains        []string        `mapstructure:"denied_domains"`         // Domaines (et sous-domaines) toujours refusés
+	PasswordRateLimit    RateLimitConfig `mapstructure:"password_rate_limit"`    // Tentatives de mot de passe autorisées par IP et par lien
+	AppSchemes           []string        `mapstructure:"app_schemes"`            // Schémas de liens profonds acceptés dans les règles par appareil (ex: itms-apps, intent)
+}
+
+// ReputationConfig définit les listes de blocage locales utilisées pour détecter
+// les destinations malveillantes (phishing, malware).
+type ReputationConfig struct {
+	Enabled         bool     `mapstructure:"enabled"`           // Active la vérification de réputation
+	DomainLists     []string `mapstructure:"domain_lists"`      // Fichiers de domaines bloqués (un par ligne)
+	HashPrefixLists []string `mapstructure:"hash_prefix_lists"` // Fichiers de préfixes SHA-256 hexadécimaux (un par ligne)
+	RefreshMinutes  int      `mapstructure:"refresh_minutes"`   // Intervalle de rechargement des fichiers modifiés
+}
+
+// GeoIPConfig contient les paramètres de la base GeoIP locale (format MaxMind .mmdb).
+type GeoIPConfig struct {
+	DatabasePath string `mapstructure:"database_path"` // Chemin du fichier .mmdb (vide = routage par pays désactivé)
+}
+
+// MetadataConfig contient les paramètres de récupération des métadonnées des destinations
+// (titre, description, favicon, og:image). Les requêtes passent par la politique de destination.
+type MetadataConfig struct {
+	Enabled        bool `mapstructure:"enabled"`         // Active la récupération à la création des liens
+	Workers        int  `mapstructure:"workers"`         // Nombre de workers de récupération
+	QueueSize      int  `mapstructure:"queue_size"`      // Taille du buffer des demandes (au-delà, les demandes sont abandonnées)
+	MaxBytes       int  `mapstructure:"max_bytes"`       // Taille maximale lue de chaque page
+	TimeoutSeconds int  `mapstructure:"timeout_seconds"` // Timeout de chaque récupération (redirections comprises)
+}
+
+// ShortCodeConfig définit la stratégie de génération des codes courts.
+type ShortCodeConfig struct {
+	Strategy         string  `mapstructure:"strategy"`          // random (défaut), sequential ou pronounceable
+	Length           int     `mapstructure:"length"`            // Longueur initiale des codes (longueur minimale en séquentiel)
+	MaxLength        int     `mapstructure:"max_length"`        // Longueur maximale atteinte par l'allongement automatique (10 au plus)
+	GrowthThreshold  float64 `mapstructure:"growth_threshold"`  // Taux de collision au-delà duquel les codes s'allongent (ex: 0.1)
+	Alphabet         string  `mapstructure:"alphabet"`          // Caractères des codes random et sequential (vide = base62)
+	ExcludeAmbiguous bool    `mapstructure:"exclude_ambiguous"` // Retire les caractères ambigus 0, O, 1, l et I
+	Encoding         string  `mapstructure:"encoding"`          // Encodage séquentiel : base62 (codes consécutifs) ou obfuscated
+	Salt             string  `mapstructure:"salt"`              // Sel de l'encodage obfuscated
+}
+
+// DedupeConfig contient le réglage par défaut de la déduplication des liens.
+type DedupeConfig struct {
+	Enabled bool `mapstructure:"enabled"` // Réutilise le lien existant vers la même URL canonique (surchargeable par requête)
+}
+
+// TrashConfig contient les paramètres de la corbeille des liens supprimés.
+type TrashConfig struct {
+	RetentionDays        int `mapstructure:"retention_days"`         // Conservation avant purge définitive (0 = jamais purgés automatiquement)
+	PurgeIntervalMinutes int `mapstructure:"purge_interval_minutes"` // Intervalle entre deux purges par le serveur
+}
+
+// WebhooksConfig contient les paramètres d'envoi des événements aux webhooks abonnés.
+// Les requêtes passent par la politique de destination.
+type WebhooksConfig struct {
+	Enabled               bool `mapstructure:"enabled"`                 // Active la publication des événements
+	Workers               int  `mapstructure:"workers"`                 // Nombre de workers d'envoi
+	QueueSize             int  `mapstructure:"queue_size"`              // Taille de la file d'envoi (au-delà, les livraisons sont reprises par le poller)
+	MaxAttempts           int  `mapstructure:"max_attempts"`            // Nombre maximal de tentatives d'une livraison
+	InitialBackoffSeconds int  `mapstructure:"initial_backoff_seconds"` // Délai avant la deuxième tentative, doublé à chaque échec
+	MaxBackoffSeconds     int  `mapstructure:"max_backoff_seconds"`     // Délai maximal entre deux tentatives
+	TimeoutSeconds        int  `mapstructure:"timeout_seconds"`         // Timeout de chaque tentative
+	PollIntervalSeconds   int  `mapstructure:"poll_interval_seconds"`   // Intervalle de reprise des livraisons dues
 }
 
 // LoadConfig charge la configuration de l'application en utilisant Viper.
@@ -63,8 +147,37 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("database.name", "url_shortener.db")
 	viper.SetDefault("analytics.buffer_size", 100)
 	viper.SetDefault("monitor.interval_minutes", 5)
+	viper.SetDefault("monitor.tls_expiry_warning_days", 14)
 	viper.SetDefault("server.rate_limit.requests", 10)
 	viper.SetDefault("server.rate_limit.window_seconds", 60)
+	viper.SetDefault("security.allowed_schemes", []string{"http", "https"})
+	viper.SetDefault("security.block_private_networks", true)
+	viper.SetDefault("security.password_rate_limit.requests", 5)
+	viper.SetDefault("security.password_rate_limit.window_seconds", 300)
+	viper.SetDefault("security.app_schemes", []string{"itms-apps", "market", "intent"})
+	viper.SetDefault("reputation.enabled", true)
+	viper.SetDefault("reputation.refresh_minutes", 10)
+	viper.SetDefault("metadata.enabled", true)
+	viper.SetDefault("metadata.workers", 2)
+	viper.SetDefault("metadata.queue_size", 1000)
+	viper.SetDefault("metadata.max_bytes", 512*1024)
+	viper.SetDefault("metadata.timeout_seconds", 5)
+	viper.SetDefault("shortcode.strategy", "random")
+	viper.SetDefault("shortcode.length", 6)
+	viper.SetDefault("shortcode.max_length", 10)
+	viper.SetDefault("shortcode.growth_threshold", 0.1)
+	viper.SetDefault("shortcode.encoding", "base62")
+	viper.SetDefault("dedupe.enabled", false)
+	viper.SetDefault("trash.retention_days", 30)
+	viper.SetDefault("trash.purge_interval_minutes", 60)
+	viper.SetDefault("webhooks.enabled", true)
+	viper.SetDefault("webhooks.workers", 2)
+	viper.SetDefault("webhooks.queue_size", 1000)
+	viper.SetDefault("webhooks.max_attempts", 6)
+	viper.SetDefault("webhooks.initial_backoff_seconds", 10)
+	viper.SetDefault("webhooks.max_backoff_seconds", 3600)
+	viper.SetDefault("webhooks.timeout_seconds", 10)
+	viper.SetDefault("webhooks.poll_interval_seconds", 5)
 
 	// Lit le fichier de configuration (ignore l'erreur si le fichier n'existe pas, les valeurs par défaut seront utilisées)
 	if err := viper.ReadInConfig(); err != nil {
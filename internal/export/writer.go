@@ -0,0 +1,95 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Formats d'export pris en charge
+const (
+	FormatCSV     = "csv"     // Valeurs séparées par des virgules, avec ligne d'en-tête
+	FormatJSONL   = "jsonl"   // Un objet JSON par ligne (JSON Lines)
+	FormatParquet = "parquet" // Format colonnaire Apache Parquet
+)
+
+// parquetRowGroupSize borne le nombre de lignes gardées en mémoire avant l'écriture d'un groupe Parquet.
+const parquetRowGroupSize = 10000
+
+// IsValidFormat indique si le format d'export est pris en charge.
+func IsValidFormat(format string) bool {
+	switch format {
+	case FormatCSV, FormatJSONL, FormatParquet:
+		return true
+	}
+	return false
+}
+
+// ContentType retourne le type MIME associé au format d'export.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSONL:
+		return "application/x-ndjson"
+	default:
+		return "application/vnd.apache.parquet"
+	}
+}
+
+// Writer écrit des lignes de type T au fil de l'eau dans le format choisi.
+// Close doit être appelé pour vider les tampons (et écrire le pied de fichier Parquet).
+type Writer[T any] struct {
+	csv     *csv.Writer
+	record  func(row *T) []string
+	json    *json.Encoder
+	parquet *parquet.GenericWriter[T]
+}
+
+// NewWriter crée un Writer vers out. Pour le CSV, header est écrit immédiatement
+// et record convertit chaque ligne en colonnes ; ces deux paramètres sont ignorés par les autres formats.
+func NewWriter[T any](out io.Writer, format string, header []string, record func(row *T) []string) (*Writer[T], error) {
+	switch format {
+	case FormatCSV:
+		w := csv.NewWriter(out)
+		if err := w.Write(header); err != nil {
+			return nil, err
+		}
+		return &Writer[T]{csv: w, record: record}, nil
+	case FormatJSONL:
+		return &Writer[T]{json: json.NewEncoder(out)}, nil
+	case FormatParquet:
+		return &Writer[T]{parquet: parquet.NewGenericWriter[T](out, parquet.MaxRowsPerRowGroup(parquetRowGroupSize))}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// Write ajoute une ligne à l'export.
+func (w *Writer[T]) Write(row *T) error {
+	switch {
+	case w.csv != nil:
+		return w.csv.Write(w.record(row))
+	case w.json != nil:
+		return w.json.Encode(row)
+	default:
+		_, err := w.parquet.Write([]T{*row})
+		return err
+	}
+}
+
+// Close termine l'export : vide le tampon CSV ou écrit le dernier groupe et le pied de fichier Parquet.
+func (w *Writer[T]) Close() error {
+	switch {
+	case w.csv != nil:
+		w.csv.Flush()
+		return w.csv.Error()
+	case w.json != nil:
+		return nil
+	default:
+		return w.parquet.Close()
+	}
+}
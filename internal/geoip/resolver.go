@@ -0,0 +1,63 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Resolver résout le pays d'une adresse IP à partir d'une base locale au format MaxMind (.mmdb),
+// par exemple GeoLite2-Country ou GeoLite2-City.
+type Resolver struct {
+	reader *maxminddb.Reader
+}
+
+// record contient les champs lus dans la base MaxMind.
+type record struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+// Open ouvre la base GeoIP située à path.
+func Open(path string) (*Resolver, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database %s: %w", path, err)
+	}
+	return &Resolver{reader: reader}, nil
+}
+
+// Country retourne le code pays ISO 3166-1 alpha-2 (ex: "FR") de l'adresse IP,
+// ou une chaîne vide si l'adresse est invalide, inconnue ou si aucune base n'est chargée.
+func (r *Resolver) Country(ipAddress string) string {
+	if r == nil || r.reader == nil {
+		return ""
+	}
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return ""
+	}
+
+	var rec record
+	if err := r.reader.Lookup(ip, &rec); err != nil {
+		return ""
+	}
+	if rec.Country.ISOCode != "" {
+		return strings.ToUpper(rec.Country.ISOCode)
+	}
+	return strings.ToUpper(rec.RegisteredCountry.ISOCode)
+}
+
+// Close libère la base GeoIP.
+func (r *Resolver) Close() error {
+	if r == nil || r.reader == nil {
+		return nil
+	}
+	return r.reader.Close()
+}
@@ -0,0 +1,216 @@
+// Package metadata récupère les métadonnées d'une page de destination
+// (titre, description, favicon, image Open Graph) pour enrichir les liens.
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// DefaultMaxBytes est la taille maximale lue d'une page de destination.
+const DefaultMaxBytes = 512 * 1024
+
+// Longueurs maximales (en caractères) des textes conservés.
+const (
+	maxTitleLength       = 300
+	maxDescriptionLength = 1000
+	maxURLLength         = 2048
+)
+
+// userAgent identifie le récupérateur auprès des sites de destination.
+const userAgent = "url-shortener-metadata/1.0 (+link preview)"
+
+// ErrNotHTML est retournée lorsque la destination ne renvoie pas une page HTML.
+var ErrNotHTML = errors.New("destination is not an HTML page")
+
+// Metadata contient les informations extraites d'une page de destination.
+// Les URLs sont absolues, résolues par rapport à l'URL finale de la page (après redirections).
+type Metadata struct {
+	Title       string
+	Description string
+	FaviconURL  string
+	ImageURL    string
+}
+
+// Fetcher télécharge et analyse les pages de destination.
+type Fetcher struct {
+	client   *http.Client // Client HTTP (celui de la politique de destination en production)
+	maxBytes int64        // Taille maximale lue de la réponse
+}
+
+// NewFetcher crée un Fetcher utilisant client, qui doit porter les protections réseau voulues
+// (policy.DestinationPolicy.NewHTTPClient en production, client d'un serveur httptest dans un test).
+// maxBytes <= 0 applique DefaultMaxBytes.
+func NewFetcher(client *http.Client, maxBytes int64) *Fetcher {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	return &Fetcher{client: client, maxBytes: maxBytes}
+}
+
+// Fetch télécharge la page rawURL et en extrait les métadonnées.
+// Seul l'en-tête du document (<head>) est analysé, dans la limite de maxBytes.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Metadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); contentType != "" &&
+		(err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml")) {
+		return nil, fmt.Errorf("%w (%s)", ErrNotHTML, contentType)
+	}
+
+	// Conversion en UTF-8 selon l'en-tête Content-Type ou la balise <meta charset>
+	body, err := charset.NewReader(io.LimitReader(resp.Body, f.maxBytes), contentType)
+	if err != nil {
+		return nil, err
+	}
+	return parse(body, resp.Request.URL)
+}
+
+// parse extrait les métadonnées de l'en-tête d'un document HTML.
+// Les balises Open Graph priment sur <title> et <meta name="description">.
+func parse(r io.Reader, base *url.URL) (*Metadata, error) {
+	var (
+		meta               Metadata
+		title, description string
+		ogTitle, ogDesc    string
+		icon, fallbackIcon string
+		inTitle            bool
+	)
+
+	tokenizer := html.NewTokenizer(r)
+loop:
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+			break loop
+		case html.TextToken:
+			if inTitle && title == "" {
+				title = string(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				break loop
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			attrs := map[string]string{}
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = tokenizer.TagAttr()
+				attrs[string(key)] = string(val)
+			}
+
+			switch string(name) {
+			case "title":
+				inTitle = tokenType == html.StartTagToken
+			case "body":
+				break loop
+			case "meta":
+				property := strings.ToLower(attrs["property"])
+				if property == "" {
+					property = strings.ToLower(attrs["name"])
+				}
+				content := attrs["content"]
+				switch property {
+				case "og:title":
+					ogTitle = firstNonEmpty(ogTitle, content)
+				case "og:description":
+					ogDesc = firstNonEmpty(ogDesc, content)
+				case "description":
+					description = firstNonEmpty(description, content)
+				case "og:image", "og:image:url", "og:image:secure_url", "twitter:image":
+					meta.ImageURL = firstNonEmpty(meta.ImageURL, resolve(base, content))
+				}
+			case "link":
+				for _, rel := range strings.Fields(strings.ToLower(attrs["rel"])) {
+					switch rel {
+					case "icon":
+						icon = firstNonEmpty(icon, resolve(base, attrs["href"]))
+					case "apple-touch-icon":
+						fallbackIcon = firstNonEmpty(fallbackIcon, resolve(base, attrs["href"]))
+					}
+				}
+			}
+		}
+	}
+
+	meta.Title = truncate(firstNonEmpty(ogTitle, title), maxTitleLength)
+	meta.Description = truncate(firstNonEmpty(ogDesc, description), maxDescriptionLength)
+	meta.FaviconURL = firstNonEmpty(icon, fallbackIcon)
+	if meta.FaviconURL == "" && base != nil {
+		// Emplacement conventionnel lorsque la page ne déclare pas d'icône
+		meta.FaviconURL = (&url.URL{Scheme: base.Scheme, Host: base.Host, Path: "/favicon.ico"}).String()
+	}
+	return &meta, nil
+}
+
+// resolve rend ref absolue par rapport à base ; seules les URLs http(s) sont conservées.
+func resolve(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || len(ref) > maxURLLength {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
+
+// firstNonEmpty retourne la première valeur non vide (espaces ignorés).
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// truncate normalise les espaces de s et le limite à max caractères.
+func truncate(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max-1]) + "…"
+}
@@ -0,0 +1,151 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestServer démarre un serveur de pages de destination et l'arrête à la fin du test.
+func newTestServer(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// htmlPage répond avec body en text/html.
+func htmlPage(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestFetchPrefersOpenGraphOverTitle(t *testing.T) {
+	server := newTestServer(t, htmlPage(`<!doctype html><html><head>
+		<title>Titre de la page</title>
+		<meta name="description" content="Description de la page">
+		<meta property="og:title" content="Titre Open Graph">
+		<meta property="og:description" content="Description Open Graph">
+		<meta property="og:image" content="/images/card.png">
+		</head><body><title>Ignoré</title></body></html>`))
+
+	meta, err := NewFetcher(server.Client(), 0).Fetch(context.Background(), server.URL+"/article")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if meta.Title != "Titre Open Graph" {
+		t.Errorf("Title = %q, want %q", meta.Title, "Titre Open Graph")
+	}
+	if meta.Description != "Description Open Graph" {
+		t.Errorf("Description = %q, want %q", meta.Description, "Description Open Graph")
+	}
+	if want := server.URL + "/images/card.png"; meta.ImageURL != want {
+		t.Errorf("ImageURL = %q, want %q", meta.ImageURL, want)
+	}
+}
+
+func TestFetchFallsBackToTitle(t *testing.T) {
+	server := newTestServer(t, htmlPage(`<html><head><title>  Titre
+		seul </title><meta name="description" content="Description"></head></html>`))
+
+	meta, err := NewFetcher(server.Client(), 0).Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if meta.Title != "Titre seul" || meta.Description != "Description" {
+		t.Errorf("Title, Description = %q, %q; want %q, %q", meta.Title, meta.Description, "Titre seul", "Description")
+	}
+}
+
+func TestFetchResolvesRelativeFavicon(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/old", http.RedirectHandler("/blog/post", http.StatusMovedPermanently))
+	mux.Handle("/blog/post", htmlPage(`<html><head>
+		<link rel="apple-touch-icon" href="/touch.png">
+		<link rel="shortcut icon" href="../static/icon.png">
+		</head></html>`))
+	mux.Handle("/bare", htmlPage(`<html><head><title>Sans icône</title></head></html>`))
+	server := newTestServer(t, mux)
+	fetcher := NewFetcher(server.Client(), 0)
+
+	// L'icône est résolue par rapport à l'URL finale, après la redirection
+	meta, err := fetcher.Fetch(context.Background(), server.URL+"/old")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if want := server.URL + "/static/icon.png"; meta.FaviconURL != want {
+		t.Errorf("FaviconURL = %q, want %q", meta.FaviconURL, want)
+	}
+
+	// Sans icône déclarée, l'emplacement conventionnel est retenu
+	meta, err = fetcher.Fetch(context.Background(), server.URL+"/bare")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if want := server.URL + "/favicon.ico"; meta.FaviconURL != want {
+		t.Errorf("FaviconURL = %q, want %q", meta.FaviconURL, want)
+	}
+}
+
+func TestFetchStopsAtMaxBytes(t *testing.T) {
+	const maxBytes = 1024
+	padding := "<!-- " + strings.Repeat("x", 2*maxBytes) + " -->"
+	mux := http.NewServeMux()
+	mux.Handle("/short", htmlPage(`<html><head><title>Dans la limite</title></head></html>`))
+	mux.Handle("/long", htmlPage(`<html><head>`+padding+`<title>Au-delà de la limite</title></head></html>`))
+	server := newTestServer(t, mux)
+	fetcher := NewFetcher(server.Client(), maxBytes)
+
+	meta, err := fetcher.Fetch(context.Background(), server.URL+"/short")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if meta.Title != "Dans la limite" {
+		t.Errorf("Title = %q, want %q", meta.Title, "Dans la limite")
+	}
+
+	meta, err = fetcher.Fetch(context.Background(), server.URL+"/long")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if meta.Title != "" {
+		t.Errorf("Title = %q, want it empty: the tag is past maxBytes", meta.Title)
+	}
+}
+
+func TestFetchRejectsNonHTML(t *testing.T) {
+	for _, contentType := range []string{"application/json", "image/png", "text/plain; charset=utf-8", "not a media type;;"} {
+		server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			_, _ = w.Write([]byte(`<html><head><title>Pas une page</title></head></html>`))
+		}))
+
+		meta, err := NewFetcher(server.Client(), 0).Fetch(context.Background(), server.URL)
+		if !errors.Is(err, ErrNotHTML) {
+			t.Errorf("Content-Type %q: err = %v, want ErrNotHTML", contentType, err)
+		}
+		if meta != nil {
+			t.Errorf("Content-Type %q: metadata = %+v, want nil", contentType, meta)
+		}
+	}
+}
+
+func TestFetchAcceptsXHTML(t *testing.T) {
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xhtml+xml")
+		_, _ = w.Write([]byte(`<html><head><title>XHTML</title></head></html>`))
+	}))
+
+	meta, err := NewFetcher(server.Client(), 0).Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if meta.Title != "XHTML" {
+		t.Errorf("Title = %q, want %q", meta.Title, "XHTML")
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActorKey est la clé du contexte Gin contenant l'auteur d'une requête d'administration.
+const ActorKey = "actor"
+
+// sharedTokenActor est l'auteur des actions faites avec le jeton partagé.
+const sharedTokenActor = "admin"
+
+// maxClaimedActorLength borne la taille du nom déclaré dans l'en-tête 'X-Admin-Actor'.
+const maxClaimedActorLength = 60
+
+// AdminAuth protège les routes d'administration par un jeton, transmis via
+// l'en-tête 'Authorization: Bearer <token>' ou 'X-Admin-Token'.
+//
+// namedTokens associe un nom d'administrateur à son jeton personnel : l'auteur des actions
+// est alors ce nom, établi par le serveur. Avec le jeton partagé sharedToken, l'auteur est "admin" ;
+// le nom éventuellement déclaré dans l'en-tête 'X-Admin-Actor' est conservé comme non vérifié
+// ("admin (unverified: bob)").
+// Sans aucun jeton configuré, les routes sont fermées (HTTP 503) : elles ne sont jamais ouvertes par défaut.
+func AdminAuth(sharedToken string, namedTokens map[string]string) gin.HandlerFunc {
+	tokens := make(map[string]string, len(namedTokens))
+	for name, token := range namedTokens {
+		if name = strings.TrimSpace(name); name != "" && token != "" {
+			tokens[name] = token
+		}
+	}
+
+	return func(c *gin.Context) {
+		if sharedToken == "" && len(tokens) == 0 {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "admin API disabled: server.admin_tokens is not configured",
+			})
+			return
+		}
+		provided := c.GetHeader("X-Admin-Token")
+		if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			provided = strings.TrimPrefix(auth, "Bearer ")
+		}
+
+		// Toutes les comparaisons sont effectuées, en temps constant, quel que soit le jeton reconnu
+		actor := ""
+		for name, token := range tokens {
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+				actor = name
+			}
+		}
+		if actor == "" && sharedToken != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(sharedToken)) == 1 {
+			actor = sharedActor(c.GetHeader("X-Admin-Actor"))
+		}
+		if actor == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "invalid or missing admin token",
+			})
+			return
+		}
+
+		c.Set(ActorKey, actor)
+		c.Next()
+	}
+}
+
+// sharedActor retourne l'auteur d'une action faite avec le jeton partagé :
+// "admin", suivi du nom déclaré par le client, marqué comme non vérifié.
+func sharedActor(claimed string) string {
+	claimed = strings.TrimSpace(claimed)
+	if claimed == "" {
+		return sharedTokenActor
+	}
+	if len(claimed) > maxClaimedActorLength {
+		claimed = strings.ToValidUTF8(claimed[:maxClaimedActorLength], "")
+	}
+	return sharedTokenActor + " (unverified: " + claimed + ")"
+}
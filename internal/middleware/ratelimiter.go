@@ -32,10 +32,18 @@ func NewRateLimiter(requests int, window time.Duration) *RateLimiter {
 	}
 }
 
-// Middleware retourne un gin.Handler qui applique la limitation.
+// Middleware retourne un gin.Handler qui applique la limitation par adresse IP.
 func (r *RateLimiter) Middleware() gin.HandlerFunc {
+	return r.MiddlewareByKey(func(c *gin.Context) string {
+		return c.ClientIP()
+	})
+}
+
+// MiddlewareByKey retourne un gin.Handler qui applique la limitation sur la clé
+// calculée par keyFunc (ex: IP + code court pour limiter les essais de mot de passe par lien).
+func (r *RateLimiter) MiddlewareByKey(keyFunc func(c *gin.Context) string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		key := c.ClientIP()
+		key := keyFunc(c)
 		if key == "" {
 			key = "unknown"
 		}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Actions enregistrées dans le journal d'audit
+const (
+	AuditLinkCreated        = "link.created"        // Création d'un lien (API, création groupée, CLI)
+	AuditLinkUpdated        = "link.updated"        // Modification de la destination (y compris retour arrière)
+	AuditLinkDeleted        = "link.deleted"        // Mise à la corbeille
+	AuditLinkRestored       = "link.restored"       // Restauration depuis la corbeille
+	AuditLinkPurged         = "link.purged"         // Suppression définitive (durée de conservation de la corbeille)
+	AuditLinkDisabled       = "link.disabled"       // Désactivation (modération, réputation)
+	AuditLinkEnabled        = "link.enabled"        // Réactivation (administrateur, destination retirée des listes de blocage)
+	AuditLinkDeactivated    = "link.deactivated"    // Destination devenue inaccessible (moniteur)
+	AuditLinkReactivated    = "link.reactivated"    // Destination de nouveau accessible (moniteur)
+	AuditReportResolved     = "report.resolved"     // Action de modération appliquée à un signalement
+	AuditDomainBanned       = "domain.banned"       // Domaine de destination banni
+	AuditWebhookCreated     = "webhook.created"     // Abonnement webhook créé
+	AuditWebhookDeleted     = "webhook.deleted"     // Abonnement webhook supprimé
+	AuditWebhookRedelivered = "webhook.redelivered" // Nouvelle livraison d'un événement demandée
+)
+
+// Types de cibles des entrées du journal d'audit
+const (
+	AuditTargetLink    = "link"    // Cible : [domaine/]code court
+	AuditTargetReport  = "report"  // Cible : identifiant du signalement
+	AuditTargetDomain  = "domain"  // Cible : nom de domaine
+	AuditTargetWebhook = "webhook" // Cible : identifiant de l'abonnement webhook
+)
+
+// ErrAuditAppendOnly est retournée par toute tentative de modification ou de suppression d'une entrée d'audit.
+var ErrAuditAppendOnly = errors.New("audit entries are append-only")
+
+// AuditChange est la valeur d'un champ avant et après une action (nil = absent).
+type AuditChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditDiff associe chaque champ modifié par une action à ses valeurs avant et après.
+type AuditDiff map[string]AuditChange
+
+// AuditEntry est une entrée du journal d'audit des actions d'administration (table en ajout seul :
+// les hooks BeforeUpdate et BeforeDelete refusent toute modification).
+type AuditEntry struct {
+	ID         uint      `gorm:"primaryKey"`                                 // Clé primaire
+	CreatedAt  time.Time `gorm:"autoCreateTime;index"`                       // Date de l'action
+	Actor      string    `gorm:"size:100;index"`                             // Auteur (administrateur, utilisateur CLI, "system:monitor"...)
+	Action     string    `gorm:"size:50;index"`                              // Action (voir les constantes Audit*)
+	TargetType string    `gorm:"size:20;index:idx_audit_target,priority:1"`  // Type de la cible (voir AuditTarget*)
+	Target     string    `gorm:"size:300;index:idx_audit_target,priority:2"` // Identifiant de la cible
+	Diff       AuditDiff `gorm:"serializer:json"`                            // Champs modifiés, valeurs avant et après
+	Note       string    `gorm:"type:text"`                                  // Motif ou commentaire facultatif
+}
+
+// BeforeUpdate empêche la modification d'une entrée d'audit.
+func (AuditEntry) BeforeUpdate(*gorm.DB) error {
+	return ErrAuditAppendOnly
+}
+
+// BeforeDelete empêche la suppression d'une entrée d'audit.
+func (AuditEntry) BeforeDelete(*gorm.DB) error {
+	return ErrAuditAppendOnly
+}
+
+// AuditTarget retourne l'identifiant d'un lien dans le journal d'audit : code court,
+// précédé du domaine pour un domaine personnalisé ("go.acme.io/promo").
+func (l *Link) AuditTarget() string {
+	if l.Domain == "" {
+		return l.ShortCode
+	}
+	return l.Domain + "/" + l.ShortCode
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Statuts possibles de la vérification TLS d'une URL longue (champ Link.TLSStatus).
+const (
+	TLSStatusValid    = "valid"    // Chaîne valide et expiration hors de la fenêtre d'alerte
+	TLSStatusExpiring = "expiring" // Chaîne valide mais le certificat expire bientôt
+	TLSStatusExpired  = "expired"  // Le certificat feuille est expiré
+	TLSStatusInvalid  = "invalid"  // Chaîne non vérifiable (autorité inconnue, nom d'hôte incorrect, ...)
+	TLSStatusError    = "error"    // Impossible d'établir la connexion TLS
+)
+
+// CertificateInfo résume un certificat de la chaîne présentée par une destination HTTPS.
+// Elle est sérialisée en JSON dans la colonne Link.TLSChain.
+type CertificateInfo struct {
+	Subject   string    `json:"subject"`    // Sujet du certificat (CN, O, ...)
+	Issuer    string    `json:"issuer"`     // Émetteur du certificat
+	DNSNames  []string  `json:"dns_names"`  // Noms DNS couverts (SAN)
+	NotBefore time.Time `json:"not_before"` // Début de validité
+	NotAfter  time.Time `json:"not_after"`  // Fin de validité
+}
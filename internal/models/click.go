@@ -2,6 +2,18 @@ package models
 
 import "time"
 
+// Provenance d'un clic, transmise par le paramètre de requête SourceQueryParam
+// de l'URL courte (ex: l'URL encodée dans les QR codes se termine par "?src=qr").
+const (
+	SourceQueryParam = "src"
+	ClickSourceQR    = "qr"
+)
+
+// IsKnownClickSource indique si la provenance est reconnue ; les autres valeurs sont ignorées.
+func IsKnownClickSource(source string) bool {
+	return source == ClickSourceQR
+}
+
 // Click représente un événement de clic sur un lien raccourci.
 // GORM utilisera ces tags pour créer la table 'clicks'.
 type Click struct {
@@ -9,15 +21,27 @@ type Click struct {
 	LinkID    uint      `gorm:"index"`             // Clé étrangère vers la table 'links', indexée pour des requêtes efficaces
 	Link      Link      `gorm:"foreignKey:LinkID"` // Relation GORM: indique que LinkID est une FK vers le champ ID de Link
 	Timestamp time.Time // Horodatage précis du clic
-	UserAgent string    `gorm:"size:255"` // User-Agent de l'utilisateur qui a cliqué (informations sur le navigateur/OS)
-	IPAddress string    `gorm:"size:50"`  // Adresse IP de l'utilisateur
+	UserAgent string    `gorm:"size:255"`      // User-Agent de l'utilisateur qui a cliqué (informations sur le navigateur/OS)
+	IPAddress string    `gorm:"size:50"`       // Adresse IP de l'utilisateur
+	Country   string    `gorm:"size:2;index"`  // Code pays ISO résolu depuis l'adresse IP (vide si inconnu)
+	Platform  string    `gorm:"size:16;index"` // Plateforme déduite du User-Agent (ios, android, windows...)
+	Device    string    `gorm:"size:16;index"` // Catégorie d'appareil déduite du User-Agent (mobile, tablet, desktop, bot)
+	Variant   string    `gorm:"size:64;index"` // Variante du test A/B vers laquelle le visiteur a été redirigé
+	Source    string    `gorm:"size:16;index"` // Provenance du clic (ex: "qr"), vide pour un lien partagé directement
 }
 
 // ClickEvent représente un événement de clic brut, destiné à être passé via un channel.
 // Ce n'est pas un modèle GORM direct, mais une structure légère pour la communication asynchrone.
 type ClickEvent struct {
 	LinkID    uint      // ID du lien cliqué
+	ShortCode string    // Code court du lien cliqué
+	Domain    string    // Domaine personnalisé du lien (vide = domaine par défaut)
 	Timestamp time.Time // Moment du clic
 	UserAgent string    // User-Agent du navigateur/client
 	IPAddress string    // Adresse IP de l'utilisateur
+	Country   string    // Code pays ISO résolu depuis l'adresse IP
+	Platform  string    // Plateforme déduite du User-Agent
+	Device    string    // Catégorie d'appareil déduite du User-Agent
+	Variant   string    // Variante du test A/B retenue
+	Source    string    // Provenance du clic (ex: "qr")
 }
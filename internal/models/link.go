@@ -1,13 +1,120 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // Link représente un lien raccourci dans la base de données.
 // Les tags `gorm:"..."` définissent comment GORM doit mapper cette structure à une table SQL.
 type Link struct {
-	ID        uint      `gorm:"primaryKey"`                    // Clé primaire auto-incrémentée
-	ShortCode string    `gorm:"uniqueIndex;size:10;not null"` // Code court unique, indexé pour recherches rapides, max 10 caractères
-	LongURL   string    `gorm:"type:text;not null"`           // URL longue originale, ne peut pas être null
-	CreatedAt time.Time `gorm:"autoCreateTime"`               // Horodatage automatique de création du lien
-	IsActive  bool      `gorm:"default:true"`                 // Indique si l'URL est accessible (utilisé par le moniteur)
+	ID        uint      `gorm:"primaryKey"`                                                                // Clé primaire auto-incrémentée
+	Domain    string    `gorm:"size:255;not null;default:'';uniqueIndex:idx_links_domain_code,priority:1"` // Domaine personnalisé du lien (vide = domaine de server.base_url)
+	ShortCode string    `gorm:"size:10;not null;uniqueIndex:idx_links_domain_code,priority:2"`             // Code court, unique par domaine, max 10 caractères
+	LongURL   string    `gorm:"type:text;not null"`                                                        // URL longue originale, ne peut pas être null
+	URLHash   string    `gorm:"size:64;index"`                                                             // Empreinte SHA-256 de l'URL longue canonique (déduplication)
+	CreatedAt time.Time `gorm:"autoCreateTime"`                                                            // Horodatage automatique de création du lien
+	IsActive  bool      `gorm:"default:true"`                                                              // Indique si l'URL est accessible (utilisé par le moniteur)
+
+	PasswordHash string `gorm:"size:100"`  // Hachage bcrypt du mot de passe protégeant la redirection (vide = lien public)
+	MaxClicks    int    `gorm:"default:0"` // Nombre maximal de redirections (0 = illimité, 1 = lien à usage unique)
+	ClickCount   int    `gorm:"default:0"` // Redirections consommées, incrémenté atomiquement pour les liens limités
+
+	// Activation programmée : en dehors de la période, le lien affiche une page d'attente ou redirige vers FallbackURL
+	StartsAt    *time.Time // Date à partir de laquelle le lien redirige (nil = immédiatement)
+	Schedule    *Schedule  `gorm:"serializer:json"` // Plages horaires quotidiennes/hebdomadaires (nil = toujours ouvert)
+	FallbackURL string     `gorm:"type:text"`       // URL de repli utilisée en dehors de la période d'activation
+
+	// Routage : destinations alternatives selon le visiteur (LongURL reste la destination par défaut)
+	DeviceRules []DeviceRule `gorm:"serializer:json"` // Règles par plateforme/appareil, évaluées en premier (User-Agent)
+	GeoRules    []GeoRule    `gorm:"serializer:json"` // Règles par pays, résolu depuis l'adresse IP (base GeoIP locale)
+	Variants    []Variant    `gorm:"serializer:json"` // Test A/B : répartition pondérée du trafic restant (remplace LongURL)
+
+	// Paramètres de requête ajoutés à la destination lors de la redirection
+	UTM           *UTM   `gorm:"serializer:json"` // Paramètres utm_* fusionnés dans la destination (nil = aucun)
+	ForwardQuery  bool   `gorm:"default:false"`   // Transmet les paramètres de la requête du visiteur à la destination
+	QueryConflict string `gorm:"size:16"`         // Règle en cas de paramètre déjà présent : keep, override ou append
+
+	// Organisation : étiquettes, campagne et dossier (chemin "marketing/2025")
+	Tags       []Tag     `gorm:"many2many:link_tags;"`  // Étiquettes (table de jointure 'link_tags')
+	CampaignID *uint     `gorm:"index"`                 // Campagne du lien (nil = aucune)
+	Campaign   *Campaign `gorm:"foreignKey:CampaignID"` // Relation GORM vers la campagne
+	Folder     string    `gorm:"size:255;index"`        // Dossier, filtrable par préfixe ("marketing" inclut "marketing/2025")
+
+	// Désactivation du lien (réputation, modération) : un lien désactivé ne redirige plus
+	IsDisabled     bool       `gorm:"default:false"` // Indique si le lien a été désactivé
+	DisabledReason string     `gorm:"type:text"`     // Raison de la désactivation
+	FlagReason     string     `gorm:"type:text"`     // Raison du signalement par la vérification de réputation (URL malveillante)
+	FlaggedAt      *time.Time // Date du signalement par la vérification de réputation
+
+	// Résultat de la dernière vérification TLS effectuée par le moniteur (destinations HTTPS uniquement)
+	TLSStatus    string            `gorm:"size:20"`   // Statut TLS (voir les constantes TLSStatus*), vide si non vérifié
+	TLSError     string            `gorm:"type:text"` // Détail de l'erreur de vérification éventuelle
+	TLSExpiresAt *time.Time        // Date d'expiration du certificat feuille
+	TLSCheckedAt *time.Time        // Date de la dernière vérification TLS
+	TLSChain     []CertificateInfo `gorm:"serializer:json"` // Chaîne de certificats présentée par le serveur
+
+	// Métadonnées de la page de destination, récupérées de manière asynchrone après la création
+	Metadata LinkMetadata `gorm:"embedded;embeddedPrefix:meta_"`
+
+	// Aperçu personnalisé servi aux robots des messageries et réseaux sociaux (balises Open Graph)
+	SocialCard SocialCard `gorm:"embedded;embeddedPrefix:og_"`
+
+	// Corbeille : un lien supprimé est exclu des requêtes GORM, ne redirige plus (HTTP 410)
+	// et garde son code court jusqu'à sa purge définitive
+	DeletedAt gorm.DeletedAt `gorm:"index"`    // Date de mise à la corbeille (NULL = lien actif)
+	DeletedBy string         `gorm:"size:100"` // Auteur de la suppression
+}
+
+// ReputationDisabledPrefix préfixe la raison de désactivation d'un lien signalé par la vérification de réputation.
+const ReputationDisabledPrefix = "reputation: "
+
+// IsDisabledByFlag indique si la désactivation du lien est celle de son signalement de réputation,
+// et non une décision de modération.
+func (l *Link) IsDisabledByFlag() bool {
+	return l.IsDisabled && l.FlaggedAt != nil && strings.HasPrefix(l.DisabledReason, ReputationDisabledPrefix)
+}
+
+// ClearFlag lève le signalement de réputation du lien et la désactivation qui l'accompagne
+// (une désactivation de modération est conservée). Retourne les champs modifiés, vide si le lien n'était pas signalé.
+func (l *Link) ClearFlag() AuditDiff {
+	diff := AuditDiff{}
+	if l.FlaggedAt == nil {
+		return diff
+	}
+	if l.IsDisabledByFlag() {
+		diff["is_disabled"] = AuditChange{Old: true, New: false}
+		diff["disabled_reason"] = AuditChange{Old: l.DisabledReason, New: ""}
+		l.IsDisabled = false
+		l.DisabledReason = ""
+	}
+	diff["flagged_at"] = AuditChange{Old: *l.FlaggedAt, New: nil}
+	diff["flag_reason"] = AuditChange{Old: l.FlagReason, New: ""}
+	l.FlaggedAt = nil
+	l.FlagReason = ""
+	return diff
+}
+
+// IsPasswordProtected indique si la redirection exige un mot de passe.
+func (l *Link) IsPasswordProtected() bool {
+	return l.PasswordHash != ""
+}
+
+// IsExhausted indique si un lien limité a atteint son nombre maximal de redirections.
+func (l *Link) IsExhausted() bool {
+	return l.MaxClicks > 0 && l.ClickCount >= l.MaxClicks
+}
+
+// IsAvailableAt indique si le lien redirige à l'instant t (date de début et plages horaires).
+func (l *Link) IsAvailableAt(t time.Time) bool {
+	if l.StartsAt != nil && t.Before(*l.StartsAt) {
+		return false
+	}
+	if l.Schedule != nil {
+		open, err := l.Schedule.IsOpen(t)
+		return err == nil && open
+	}
+	return true
 }
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// LinkMetadata contient les métadonnées extraites de la page de destination d'un lien
+// (colonnes meta_* de la table links).
+type LinkMetadata struct {
+	Title       string     `gorm:"type:text"` // Titre de la page (og:title ou <title>)
+	Description string     `gorm:"type:text"` // Description (og:description ou <meta name="description">)
+	FaviconURL  string     `gorm:"type:text"` // URL absolue de l'icône du site
+	ImageURL    string     `gorm:"type:text"` // URL absolue de l'image Open Graph (og:image)
+	Error       string     `gorm:"type:text"` // Erreur de la dernière récupération (vide si réussie)
+	FetchedAt   *time.Time // Date de la dernière récupération (nil = pas encore récupérées)
+}
+
+// MetadataJob demande la récupération des métadonnées de la destination d'un lien.
+// Il est transmis aux workers de métadonnées via un channel bufferisé.
+type MetadataJob struct {
+	LinkID  uint
+	LongURL string
+}
+
+// SocialCard contient les balises Open Graph / Twitter Card servies aux robots d'aperçu
+// des messageries et réseaux sociaux (colonnes og_* de la table links).
+// Un champ vide reprend la valeur des métadonnées récupérées sur la destination.
+type SocialCard struct {
+	Title       string `gorm:"type:text" json:"title"`       // og:title / twitter:title
+	Description string `gorm:"type:text" json:"description"` // og:description / twitter:description
+	ImageURL    string `gorm:"type:text" json:"image_url"`   // og:image / twitter:image (URL absolue http ou https)
+}
+
+// IsEmpty indique si aucune balise n'a été personnalisée.
+func (c SocialCard) IsEmpty() bool {
+	return c.Title == "" && c.Description == "" && c.ImageURL == ""
+}
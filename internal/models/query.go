@@ -0,0 +1,50 @@
+package models
+
+import "net/url"
+
+// Règles de résolution des conflits lorsqu'un paramètre transmis par le visiteur
+// existe déjà dans l'URL de destination.
+const (
+	QueryConflictKeep     = "keep"     // La valeur de la destination est conservée (par défaut)
+	QueryConflictOverride = "override" // La valeur du visiteur remplace celle de la destination
+	QueryConflictAppend   = "append"   // Les deux valeurs sont conservées (paramètre répété)
+)
+
+// UTM regroupe les paramètres de suivi de campagne ajoutés à la destination lors de la redirection.
+// Elle est sérialisée en JSON dans la colonne Link.UTM.
+type UTM struct {
+	Source   string `json:"source,omitempty"`   // utm_source (ex: "newsletter")
+	Medium   string `json:"medium,omitempty"`   // utm_medium (ex: "email")
+	Campaign string `json:"campaign,omitempty"` // utm_campaign (ex: "soldes-ete")
+	Term     string `json:"term,omitempty"`     // utm_term (mots-clés payants)
+	Content  string `json:"content,omitempty"`  // utm_content (différencie les liens d'un même contenu)
+}
+
+// Values retourne les paramètres utm_* renseignés.
+func (u *UTM) Values() url.Values {
+	values := url.Values{}
+	if u == nil {
+		return values
+	}
+	for key, value := range map[string]string{
+		"utm_source":   u.Source,
+		"utm_medium":   u.Medium,
+		"utm_campaign": u.Campaign,
+		"utm_term":     u.Term,
+		"utm_content":  u.Content,
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	return values
+}
+
+// IsValidQueryConflict indique si la règle de conflit est reconnue (vide = QueryConflictKeep).
+func IsValidQueryConflict(mode string) bool {
+	switch mode {
+	case "", QueryConflictKeep, QueryConflictOverride, QueryConflictAppend:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Statuts d'un signalement d'abus.
+const (
+	ReportStatusOpen      = "open"      // En attente de traitement
+	ReportStatusActioned  = "actioned"  // Une action de modération a été appliquée
+	ReportStatusDismissed = "dismissed" // Rejeté sans action
+)
+
+// Actions de modération applicables à un signalement.
+const (
+	ModerationActionDisableLink = "disable_link" // Désactive le lien signalé
+	ModerationActionBanDomain   = "ban_domain"   // Bannit le domaine de destination et désactive le lien
+	ModerationActionDismiss     = "dismiss"      // Rejette le signalement
+)
+
+// Report représente un signalement d'abus envoyé par un destinataire d'un lien court.
+type Report struct {
+	ID            uint       `gorm:"primaryKey"`                   // Clé primaire
+	LinkID        uint       `gorm:"index"`                        // Lien signalé
+	Link          Link       `gorm:"foreignKey:LinkID"`            // Relation GORM vers le lien
+	Reason        string     `gorm:"size:50;not null"`             // Catégorie (phishing, malware, spam, other)
+	Details       string     `gorm:"type:text"`                    // Description libre fournie par l'auteur
+	ReporterEmail string     `gorm:"size:255"`                     // Contact facultatif de l'auteur
+	ReporterIP    string     `gorm:"size:50"`                      // Adresse IP de l'auteur
+	Status        string     `gorm:"size:20;index;default:'open'"` // Statut de traitement (voir ReportStatus*)
+	CreatedAt     time.Time  `gorm:"autoCreateTime"`               // Date du signalement
+	ResolvedAt    *time.Time // Date de traitement
+}
+
+// ModerationAction trace une action de modération appliquée à un signalement (piste d'audit).
+// Les enregistrements ne sont jamais modifiés ni supprimés.
+type ModerationAction struct {
+	ID        uint      `gorm:"primaryKey"`     // Clé primaire
+	ReportID  uint      `gorm:"index"`          // Signalement concerné
+	LinkID    uint      `gorm:"index"`          // Lien concerné
+	Action    string    `gorm:"size:30"`        // Action appliquée (voir ModerationAction*)
+	Actor     string    `gorm:"size:100"`       // Auteur de l'action (administrateur, utilisateur CLI)
+	Note      string    `gorm:"type:text"`      // Commentaire facultatif
+	CreatedAt time.Time `gorm:"autoCreateTime"` // Date de l'action
+}
+
+// BannedDomain représente un domaine de destination banni par la modération.
+// Les liens vers ce domaine (et ses sous-domaines) ne peuvent plus être créés.
+type BannedDomain struct {
+	ID        uint      `gorm:"primaryKey"`           // Clé primaire
+	Domain    string    `gorm:"uniqueIndex;size:255"` // Domaine banni, en minuscules
+	Reason    string    `gorm:"type:text"`            // Motif du bannissement
+	Actor     string    `gorm:"size:100"`             // Auteur du bannissement
+	CreatedAt time.Time `gorm:"autoCreateTime"`       // Date du bannissement
+}
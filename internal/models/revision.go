@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// LinkRevision trace une modification de la destination d'un lien (historique en ajout seul).
+// Les révisions sont numérotées à partir de 1 pour chaque lien ; la révision 0 désigne
+// la destination d'origine, donnée par l'ancienne URL de la révision 1.
+type LinkRevision struct {
+	ID         uint      `gorm:"primaryKey"`                                        // Clé primaire
+	LinkID     uint      `gorm:"not null;uniqueIndex:idx_link_revision,priority:1"` // Lien modifié
+	Revision   int       `gorm:"not null;uniqueIndex:idx_link_revision,priority:2"` // Numéro de la révision pour ce lien
+	OldURL     string    `gorm:"type:text;not null"`                                // Destination avant la modification
+	NewURL     string    `gorm:"type:text;not null"`                                // Destination après la modification
+	Actor      string    `gorm:"size:100"`                                          // Auteur de la modification (administrateur, utilisateur CLI)
+	Note       string    `gorm:"type:text"`                                         // Commentaire facultatif
+	RollbackOf *int      // Révision restaurée, si la modification est un retour arrière
+	CreatedAt  time.Time `gorm:"autoCreateTime"` // Date de la modification
+}
@@ -0,0 +1,46 @@
+package models
+
+import "strings"
+
+// GeoRule redirige les visiteurs de certains pays vers une destination spécifique.
+// Les règles sont sérialisées en JSON dans la colonne Link.GeoRules et évaluées dans l'ordre.
+type GeoRule struct {
+	Countries []string `json:"countries"` // Codes pays ISO 3166-1 alpha-2 (ex: ["FR", "BE"])
+	URL       string   `json:"url"`       // Destination pour ces pays
+}
+
+// Matches indique si la règle s'applique au pays donné.
+func (r GeoRule) Matches(country string) bool {
+	for _, c := range r.Countries {
+		if strings.EqualFold(c, country) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeviceRule redirige les visiteurs de certaines plateformes ou catégories d'appareils
+// vers une destination spécifique (lien profond d'application, store, version web...).
+// Les règles sont sérialisées en JSON dans la colonne Link.DeviceRules et évaluées dans l'ordre.
+type DeviceRule struct {
+	Targets []string `json:"targets"` // Plateformes (ios, android, windows, macos, linux) ou appareils (mobile, tablet, desktop)
+	URL     string   `json:"url"`     // Destination pour ces visiteurs
+}
+
+// Matches indique si la règle s'applique à la plateforme ou à la catégorie d'appareil données.
+func (r DeviceRule) Matches(platform, device string) bool {
+	for _, t := range r.Targets {
+		if strings.EqualFold(t, platform) || strings.EqualFold(t, device) {
+			return true
+		}
+	}
+	return false
+}
+
+// Variant est une destination d'un test A/B. Le trafic qui n'est capté par aucune règle
+// est réparti entre les variantes proportionnellement à leur poids.
+type Variant struct {
+	Name   string `json:"name"`   // Identifiant de la variante (ex: "A"), enregistré avec chaque clic
+	URL    string `json:"url"`    // Destination de la variante
+	Weight int    `json:"weight"` // Poids relatif (0 = variante suspendue)
+}
@@ -0,0 +1,200 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // Embarque la base des fuseaux horaires pour les systèmes qui n'en disposent pas
+)
+
+// weekdays associe les abréviations acceptées aux jours de la semaine.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// weekdayOrder liste les abréviations dans l'ordre de la semaine (pour les plages "mon-fri").
+var weekdayOrder = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+// Schedule définit les plages horaires pendant lesquelles un lien redirige.
+// Elle est sérialisée en JSON dans la colonne Link.Schedule.
+type Schedule struct {
+	Timezone string       `json:"timezone"` // Fuseau horaire IANA (ex: "Europe/Paris"), UTC si vide
+	Windows  []TimeWindow `json:"windows"`  // Plages d'ouverture ; le lien est accessible si l'une d'elles est ouverte
+}
+
+// TimeWindow est une plage horaire quotidienne ou hebdomadaire.
+type TimeWindow struct {
+	Days  []string `json:"days,omitempty"` // Jours concernés (mon, tue, ...) ; vide = tous les jours
+	Start string   `json:"start"`          // Heure d'ouverture "HH:MM"
+	End   string   `json:"end"`            // Heure de fermeture "HH:MM" (exclue) ; avant Start = plage passant minuit
+}
+
+// Location retourne le fuseau horaire du calendrier.
+func (s *Schedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+// Validate vérifie le fuseau horaire, les jours et les heures de chaque plage.
+func (s *Schedule) Validate() error {
+	if _, err := s.Location(); err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
+	}
+	if len(s.Windows) == 0 {
+		return errors.New("schedule must contain at least one window")
+	}
+	for i, w := range s.Windows {
+		for _, day := range w.Days {
+			if _, ok := weekdays[strings.ToLower(day)]; !ok {
+				return fmt.Errorf("window %d: invalid day %q", i+1, day)
+			}
+		}
+		start, err := parseClock(w.Start)
+		if err != nil {
+			return fmt.Errorf("window %d: invalid start: %w", i+1, err)
+		}
+		end, err := parseClock(w.End)
+		if err != nil {
+			return fmt.Errorf("window %d: invalid end: %w", i+1, err)
+		}
+		if start == end {
+			return fmt.Errorf("window %d: start and end must differ", i+1)
+		}
+	}
+	return nil
+}
+
+// IsOpen indique si l'instant t tombe dans l'une des plages du calendrier.
+func (s *Schedule) IsOpen(t time.Time) (bool, error) {
+	loc, err := s.Location()
+	if err != nil {
+		return false, err
+	}
+	local := t.In(loc)
+	minutes := local.Hour()*60 + local.Minute()
+
+	for _, w := range s.Windows {
+		start, err := parseClock(w.Start)
+		if err != nil {
+			return false, err
+		}
+		end, err := parseClock(w.End)
+		if err != nil {
+			return false, err
+		}
+
+		if start < end {
+			// Plage dans la journée : 09:00-18:00
+			if w.includesDay(local.Weekday()) && minutes >= start && minutes < end {
+				return true, nil
+			}
+			continue
+		}
+
+		// Plage passant minuit : 22:00-02:00 (la fin appartient au lendemain du jour indiqué)
+		if w.includesDay(local.Weekday()) && minutes >= start {
+			return true, nil
+		}
+		if w.includesDay(local.AddDate(0, 0, -1).Weekday()) && minutes < end {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// includesDay indique si la plage s'applique au jour donné.
+func (w TimeWindow) includesDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if weekdays[strings.ToLower(d)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTimeWindow lit une plage au format texte utilisé par la CLI :
+//
+//	"09:00-18:00"              tous les jours
+//	"mon-fri 09:00-18:00"      du lundi au vendredi
+//	"sat,sun 10:00-12:00"      le week-end
+func ParseTimeWindow(spec string) (TimeWindow, error) {
+	fields := strings.Fields(strings.ToLower(spec))
+	var window TimeWindow
+
+	switch len(fields) {
+	case 1:
+	case 2:
+		days, err := parseDays(fields[0])
+		if err != nil {
+			return window, err
+		}
+		window.Days = days
+	default:
+		return window, fmt.Errorf("invalid window %q (expected \"[days] HH:MM-HH:MM\")", spec)
+	}
+
+	hours := strings.SplitN(fields[len(fields)-1], "-", 2)
+	if len(hours) != 2 {
+		return window, fmt.Errorf("invalid hours in window %q", spec)
+	}
+	window.Start, window.End = hours[0], hours[1]
+	return window, nil
+}
+
+// parseDays lit une liste de jours ("mon,wed") ou une plage de jours ("mon-fri").
+func parseDays(spec string) ([]string, error) {
+	var days []string
+	for _, part := range strings.Split(spec, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		for _, b := range bounds {
+			if _, ok := weekdays[b]; !ok {
+				return nil, fmt.Errorf("invalid day %q", b)
+			}
+		}
+		if len(bounds) == 1 {
+			days = append(days, bounds[0])
+			continue
+		}
+
+		// Plage de jours dans l'ordre de la semaine, éventuellement sur deux semaines (fri-mon)
+		from, to := indexOfDay(bounds[0]), indexOfDay(bounds[1])
+		for i := from; ; i = (i + 1) % len(weekdayOrder) {
+			days = append(days, weekdayOrder[i])
+			if i == to {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// indexOfDay retourne la position d'un jour dans weekdayOrder.
+func indexOfDay(day string) int {
+	for i, d := range weekdayOrder {
+		if d == day {
+			return i
+		}
+	}
+	return 0
+}
+
+// parseClock convertit "HH:MM" en minutes depuis minuit.
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,8 @@
+package models
+
+// Sequence est un compteur persistant nommé (table 'sequences'), utilisé par la génération
+// séquentielle des codes courts.
+type Sequence struct {
+	Name  string `gorm:"primaryKey;size:64"` // Nom de la séquence (ex: "short_code")
+	Value uint64 `gorm:"not null"`           // Dernière valeur attribuée
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Tag est une étiquette libre associée aux liens (relation many-to-many via la table 'link_tags').
+type Tag struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"uniqueIndex;size:64"` // Nom normalisé (minuscules)
+	CreatedAt time.Time
+}
+
+// Campaign regroupe des liens d'une même opération marketing. Un lien appartient au plus à une campagne.
+type Campaign struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"uniqueIndex;size:100"` // Nom de la campagne
+	CreatedAt time.Time
+}
@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+// Événements pouvant faire l'objet d'un abonnement webhook
+const (
+	WebhookLinkCreated       = "link.created"        // Lien créé (API, création groupée, CLI)
+	WebhookLinkClicked       = "link.clicked"        // Clic enregistré par les workers de clics
+	WebhookLinkHealthChanged = "link.health_changed" // Accessibilité ou statut TLS modifié par le moniteur
+)
+
+// WebhookEvents liste les événements acceptés dans les abonnements.
+var WebhookEvents = []string{WebhookLinkCreated, WebhookLinkClicked, WebhookLinkHealthChanged}
+
+// Statuts d'une livraison de webhook
+const (
+	WebhookDeliveryPending   = "pending"   // En attente d'une (nouvelle) tentative
+	WebhookDeliverySucceeded = "succeeded" // Acceptée par le destinataire (réponse HTTP 2xx)
+	WebhookDeliveryFailed    = "failed"    // Abandonnée après le nombre maximal de tentatives
+)
+
+// WebhookSubscription est un abonnement d'un système externe à des événements :
+// chaque événement est envoyé en POST à URL, signé avec Secret (HMAC-SHA256).
+type WebhookSubscription struct {
+	ID          uint      `gorm:"primaryKey"`         // Clé primaire
+	URL         string    `gorm:"type:text;not null"` // URL de réception des événements
+	Secret      string    `gorm:"size:100;not null"`  // Secret partagé de la signature des livraisons
+	Events      []string  `gorm:"serializer:json"`    // Événements souscrits (voir WebhookEvents)
+	Description string    `gorm:"size:255"`           // Description libre (système destinataire)
+	CreatedAt   time.Time `gorm:"autoCreateTime"`     // Date de création de l'abonnement
+}
+
+// Accepts indique si l'abonnement porte sur l'événement.
+func (s *WebhookSubscription) Accepts(event string) bool {
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery est l'envoi d'un événement à un abonnement (journal des livraisons).
+// Une livraison en échec est retentée avec un délai croissant jusqu'au nombre maximal de tentatives ;
+// une nouvelle livraison (RedeliveryOf) peut ensuite être demandée.
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey"`                      // Clé primaire
+	SubscriptionID uint       `gorm:"index"`                           // Abonnement destinataire
+	EventID        string     `gorm:"size:32;index"`                   // Identifiant de l'événement, commun aux abonnements et aux nouvelles livraisons
+	Event          string     `gorm:"size:50"`                         // Type d'événement (voir WebhookEvents)
+	Payload        string     `gorm:"type:text"`                       // Corps JSON envoyé
+	Status         string     `gorm:"size:20;index;default:'pending'"` // Statut (voir WebhookDelivery*)
+	Attempts       int        `gorm:"default:0"`                       // Nombre de tentatives effectuées
+	LastStatusCode int        // Code HTTP de la dernière réponse (0 = pas de réponse)
+	LastError      string     `gorm:"type:text"` // Erreur de la dernière tentative
+	NextAttemptAt  *time.Time `gorm:"index"`     // Date de la prochaine tentative (nil = aucune)
+	DeliveredAt    *time.Time // Date de la livraison réussie
+	RedeliveryOf   *uint      // Livraison d'origine, pour une nouvelle livraison demandée manuellement
+	CreatedAt      time.Time  `gorm:"autoCreateTime"` // Date de création de la livraison
+}
+
+// WebhookLinkData est la donnée (data) des événements link.created.
+type WebhookLinkData struct {
+	ShortCode    string    `json:"short_code"`
+	Domain       string    `json:"domain,omitempty"`
+	FullShortURL string    `json:"full_short_url"`
+	LongURL      string    `json:"long_url"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// WebhookClickData est la donnée des événements link.clicked. L'adresse IP du visiteur n'est pas transmise.
+type WebhookClickData struct {
+	ShortCode string    `json:"short_code"`
+	Domain    string    `json:"domain,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	Country   string    `json:"country,omitempty"`
+	Platform  string    `json:"platform,omitempty"`
+	Device    string    `json:"device,omitempty"`
+	Variant   string    `json:"variant,omitempty"`
+	Source    string    `json:"source,omitempty"`
+}
+
+// WebhookHealthData est la donnée des événements link.health_changed :
+// accessibilité de la destination et statut de son certificat TLS, avant et après la vérification.
+type WebhookHealthData struct {
+	ShortCode         string    `json:"short_code"`
+	Domain            string    `json:"domain,omitempty"`
+	LongURL           string    `json:"long_url"`
+	IsActive          bool      `json:"is_active"`
+	PreviousIsActive  bool      `json:"previous_is_active"`
+	TLSStatus         string    `json:"tls_status,omitempty"`
+	PreviousTLSStatus string    `json:"previous_tls_status,omitempty"`
+	TLSError          string    `json:"tls_error,omitempty"`
+	CheckedAt         time.Time `json:"checked_at"`
+}
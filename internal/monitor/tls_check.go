@@ -0,0 +1,115 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+)
+
+// tlsDialTimeout borne la durée de la poignée de main TLS.
+const tlsDialTimeout = 5 * time.Second
+
+// tlsCheckResult contient le résultat d'une vérification TLS pour une destination HTTPS.
+type tlsCheckResult struct {
+	Status    string
+	Error     string
+	ExpiresAt *time.Time
+	Chain     []models.CertificateInfo
+}
+
+// checkTLS se connecte à la destination HTTPS, récupère la chaîne de certificats
+// et la vérifie (autorité, nom d'hôte, expiration).
+// La connexion est établie sans vérification automatique afin de pouvoir capturer
+// la chaîne même lorsqu'elle est invalide ; la vérification est faite ensuite manuellement.
+func (m *UrlMonitor) checkTLS(rawURL string) tlsCheckResult {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return tlsCheckResult{Status: models.TLSStatusError, Error: err.Error()}
+	}
+
+	host := u.Hostname()
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tlsDialTimeout)
+	defer cancel()
+
+	// La connexion TCP passe par la politique de destination (adresses internes interdites)
+	rawConn, err := m.policy.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return tlsCheckResult{Status: models.TLSStatusError, Error: err.Error()}
+	}
+	conn := tls.Client(rawConn, &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true, // Vérification faite manuellement ci-dessous
+	})
+	defer conn.Close()
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return tlsCheckResult{Status: models.TLSStatusError, Error: err.Error()}
+	}
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return tlsCheckResult{Status: models.TLSStatusInvalid, Error: "no certificate presented"}
+	}
+
+	result := tlsCheckResult{Chain: make([]models.CertificateInfo, 0, len(certs))}
+	for _, cert := range certs {
+		result.Chain = append(result.Chain, models.CertificateInfo{
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			DNSNames:  cert.DNSNames,
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+		})
+	}
+
+	leaf := certs[0]
+	expiresAt := leaf.NotAfter
+	result.ExpiresAt = &expiresAt
+
+	now := time.Now()
+	if now.After(leaf.NotAfter) {
+		result.Status = models.TLSStatusExpired
+		result.Error = fmt.Sprintf("certificate expired on %s", leaf.NotAfter.Format(time.RFC3339))
+		return result
+	}
+
+	// Vérifie la chaîne en utilisant les certificats intermédiaires présentés par le serveur
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	}); err != nil {
+		result.Status = models.TLSStatusInvalid
+		result.Error = err.Error()
+		return result
+	}
+
+	if m.tlsExpiryWindow > 0 && leaf.NotAfter.Sub(now) <= m.tlsExpiryWindow {
+		result.Status = models.TLSStatusExpiring
+		result.Error = fmt.Sprintf("certificate expires on %s", leaf.NotAfter.Format(time.RFC3339))
+		return result
+	}
+
+	result.Status = models.TLSStatusValid
+	return result
+}
+
+// isHTTPS indique si l'URL utilise le schéma https.
+func isHTTPS(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Scheme == "https"
+}
@@ -1,32 +1,78 @@
 package monitor
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"sync" // Pour protéger l'accès concurrentiel à knownStates
 	"time"
 
-	_ "github.com/Quanghng/url-shortener/internal/models"   // Importe les modèles de liens
+	"github.com/Quanghng/url-shortener/internal/models"     // Importe les modèles de liens
+	"github.com/Quanghng/url-shortener/internal/policy"     // Politique de destination (protection SSRF)
 	"github.com/Quanghng/url-shortener/internal/repository" // Importe le repository de liens
+	"github.com/Quanghng/url-shortener/internal/reputation" // Vérification des listes de blocage
+	"gorm.io/gorm"
 )
 
 // UrlMonitor gère la surveillance périodique des URLs longues.
 type UrlMonitor struct {
-	linkRepo    repository.LinkRepository // Pour récupérer les URLs à surveiller
-	interval    time.Duration             // Intervalle entre chaque vérification (ex: 5 minutes)
-	knownStates map[uint]bool             // État connu de chaque URL: map[LinkID]estAccessible (true/false)
-	mu          sync.Mutex                // Mutex pour protéger l'accès concurrentiel à knownStates
+	linkRepo        repository.LinkRepository // Pour récupérer les URLs à surveiller
+	policy          *policy.DestinationPolicy // Vérifie chaque connexion sortante (adresses internes interdites)
+	client          *http.Client              // Client HTTP dont les connexions passent par la politique
+	checker         *reputation.Checker       // Vérifie les destinations contre les listes de blocage (optionnel)
+	interval        time.Duration             // Intervalle entre chaque vérification (ex: 5 minutes)
+	tlsExpiryWindow time.Duration             // Fenêtre avant expiration d'un certificat déclenchant une alerte
+	knownStates     map[uint]bool             // État connu de chaque URL: map[LinkID]estAccessible (true/false)
+	mu              sync.Mutex                // Mutex pour protéger l'accès concurrentiel à knownStates
+	audit           AuditLogger               // Journal d'audit des changements d'état (optionnel, voir SetAuditLog)
+	events          EventPublisher            // Publication des changements de santé aux webhooks (optionnel, voir SetEventPublisher)
 }
 
+// AuditLogger enregistre les changements d'état effectués par le moniteur dans le journal d'audit,
+// dans la transaction tx du changement.
+type AuditLogger interface {
+	Record(tx repository.Tx, entry models.AuditEntry) error
+}
+
+// EventPublisher publie les changements de santé des liens aux webhooks abonnés.
+type EventPublisher interface {
+	Publish(event string, data interface{})
+}
+
+// monitorActor est l'auteur des changements d'état du moniteur dans le journal d'audit.
+const monitorActor = "system:monitor"
+
 // NewUrlMonitor crée et retourne une nouvelle instance de UrlMonitor.
-func NewUrlMonitor(linkRepo repository.LinkRepository, interval time.Duration) *UrlMonitor {
+// tlsExpiryWindow définit à partir de quand un certificat proche de l'expiration est signalé.
+// Toutes les connexions du moniteur passent par destPolicy afin de ne jamais contacter d'adresse interne.
+// Si checker est fourni, les liens dont la destination apparaît dans une liste de blocage sont désactivés,
+// puis réactivés lorsqu'elle en est retirée.
+func NewUrlMonitor(linkRepo repository.LinkRepository, destPolicy *policy.DestinationPolicy, checker *reputation.Checker, interval, tlsExpiryWindow time.Duration) *UrlMonitor {
 	return &UrlMonitor{
-		linkRepo:    linkRepo,
-		interval:    interval,
-		knownStates: make(map[uint]bool), // Initialise la map pour stocker les états
+		linkRepo:        linkRepo,
+		policy:          destPolicy,
+		checker:         checker,
+		client:          destPolicy.NewHTTPClient(5 * time.Second), // Timeout pour éviter de bloquer trop longtemps
+		interval:        interval,
+		tlsExpiryWindow: tlsExpiryWindow,
+		knownStates:     make(map[uint]bool), // Initialise la map pour stocker les états
 	}
 }
 
+// SetAuditLog définit le journal d'audit dans lequel le moniteur trace les liens qu'il désactive ou réactive,
+// ou dont il change l'état d'accessibilité.
+func (m *UrlMonitor) SetAuditLog(audit AuditLogger) {
+	m.audit = audit
+}
+
+// SetEventPublisher définit la publication des événements link.health_changed
+// (accessibilité de la destination ou statut TLS modifié).
+func (m *UrlMonitor) SetEventPublisher(events EventPublisher) {
+	m.events = events
+}
+
 // Start lance la boucle de surveillance périodique des URLs.
 // Cette fonction est conçue pour être lancée dans une goroutine séparée.
 func (m *UrlMonitor) Start() {
@@ -56,6 +102,25 @@ func (m *UrlMonitor) checkUrls() {
 
 	for i := range links {
 		link := &links[i]
+
+		// Vérifie la réputation de la destination : un lien signalé est désactivé automatiquement,
+		// et réactivé lorsque sa destination est retirée des listes de blocage
+		if m.checker != nil {
+			verdict := m.checker.Check(link.LongURL)
+			switch {
+			case verdict.Flagged && link.FlaggedAt == nil:
+				m.flagLink(link, verdict)
+			case !verdict.Flagged && link.FlaggedAt != nil:
+				if !m.unflagLink(link) {
+					continue
+				}
+			}
+		}
+		// Une destination malveillante n'est plus contactée par le moniteur
+		if link.FlaggedAt != nil {
+			continue
+		}
+
 		// Pour chaque lien, vérifie son accessibilité
 		currentState := m.isUrlAccessible(link.LongURL)
 
@@ -68,15 +133,51 @@ func (m *UrlMonitor) checkUrls() {
 		m.knownStates[link.ID] = currentState // Met à jour l'état actuel
 		m.mu.Unlock()
 
+		// Pour les destinations HTTPS, vérifie aussi la chaîne de certificats
+		previousTLSStatus := link.TLSStatus
+		tlsChecked := false
+		if isHTTPS(link.LongURL) {
+			m.applyTLSResult(link, m.checkTLS(link.LongURL))
+			tlsChecked = true
+		}
+
 		// Synchronise l'état en base si nécessaire
-		if currentState != link.IsActive {
+		previousActive := link.IsActive
+		healthChanged := false
+		if currentState != link.IsActive || tlsChecked {
+			stateChanged := currentState != link.IsActive
 			link.IsActive = currentState
-			if err := m.linkRepo.UpdateLink(link); err != nil {
+			var action string
+			if stateChanged {
+				action = models.AuditLinkDeactivated
+				if currentState {
+					action = models.AuditLinkReactivated
+				}
+			}
+			err := m.updateLink(link, repository.LinkRepository.UpdateHealth, action,
+				models.AuditDiff{"is_active": {Old: !currentState, New: currentState}})
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// Lien mis à la corbeille ou destination modifiée pendant la vérification : résultat obsolète
+				log.Printf("[MONITOR] Lien %s modifié ou supprimé pendant la vérification, état ignoré.", link.ShortCode)
+				continue
+			} else if err != nil {
 				log.Printf("[MONITOR] ERREUR lors de la mise à jour de l'état du lien %s (%s) : %v",
 					link.ShortCode, link.LongURL, err)
+			} else if stateChanged {
+				healthChanged = true
 			}
 		}
 
+		// Notifie un changement de statut TLS (certificat bientôt expiré, invalide, de nouveau valide...)
+		if tlsChecked && link.TLSStatus != previousTLSStatus && (previousTLSStatus != "" || link.TLSStatus != models.TLSStatusValid) {
+			m.notify(link, fmt.Sprintf("voit son certificat TLS passer de %s à %s (%s)",
+				formatTLSStatus(previousTLSStatus), formatTLSStatus(link.TLSStatus), link.TLSError))
+			healthChanged = true
+		}
+		if healthChanged {
+			m.publishHealth(link, previousActive, previousTLSStatus)
+		}
+
 		// Si c'est la première vérification pour ce lien, on initialise l'état sans notifier.
 		if !exists {
 			log.Printf("[MONITOR] État initial pour le lien %s (%s) : %s",
@@ -87,8 +188,7 @@ func (m *UrlMonitor) checkUrls() {
 		// Compare l'état actuel avec l'état précédent
 		// Si l'état a changé, génère une notification dans les logs
 		if currentState != previousState {
-			log.Printf("[NOTIFICATION] Le lien %s (%s) est passé de %s à %s !",
-				link.ShortCode, link.LongURL, formatState(previousState), formatState(currentState))
+			m.notify(link, fmt.Sprintf("est passé de %s à %s !", formatState(previousState), formatState(currentState)))
 		}
 	}
 	log.Println("[MONITOR] Vérification de l'état des URLs terminée.")
@@ -96,13 +196,8 @@ func (m *UrlMonitor) checkUrls() {
 
 // isUrlAccessible effectue une requête HTTP HEAD pour vérifier l'accessibilité d'une URL.
 func (m *UrlMonitor) isUrlAccessible(url string) bool {
-	// Définit un timeout pour éviter de bloquer trop longtemps
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
-
 	// Effectue une requête HEAD (plus légère que GET) sur l'URL
-	resp, err := client.Head(url)
+	resp, err := m.client.Head(url)
 	if err != nil {
 		log.Printf("[MONITOR] Erreur d'accès à l'URL '%s': %v", url, err)
 		return false
@@ -115,6 +210,119 @@ func (m *UrlMonitor) isUrlAccessible(url string) bool {
 	return resp.StatusCode >= 200 && resp.StatusCode < 400 // Codes 2xx ou 3xx
 }
 
+// flagLink marque un lien comme malveillant, le désactive et émet une notification.
+func (m *UrlMonitor) flagLink(link *models.Link, verdict reputation.Verdict) {
+	wasDisabled, previousReason := link.IsDisabled, link.DisabledReason
+	now := time.Now()
+	link.FlaggedAt = &now
+	link.FlagReason = fmt.Sprintf("%s (%s)", verdict.Reason, verdict.Source)
+	link.IsDisabled = true
+	link.DisabledReason = models.ReputationDisabledPrefix + link.FlagReason
+
+	err := m.updateLink(link, repository.LinkRepository.UpdateFlag, models.AuditLinkDisabled, models.AuditDiff{
+		"is_disabled":     {Old: wasDisabled, New: true},
+		"disabled_reason": {Old: previousReason, New: link.DisabledReason},
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("[MONITOR] Lien %s modifié ou supprimé pendant la vérification, signalement ignoré.", link.ShortCode)
+		return
+	}
+	if err != nil {
+		log.Printf("[MONITOR] ERREUR lors de la désactivation du lien %s (%s) : %v",
+			link.ShortCode, link.LongURL, err)
+		return
+	}
+	m.notify(link, "a été désactivé : destination signalée comme malveillante - "+link.FlagReason)
+}
+
+// unflagLink lève le signalement d'un lien dont la destination n'est plus listée, ainsi que
+// la désactivation qui l'accompagne, et émet une notification. Retourne false si le lien n'a pas pu être mis à jour.
+func (m *UrlMonitor) unflagLink(link *models.Link) bool {
+	flagReason := link.FlagReason
+	diff := link.ClearFlag()
+
+	err := m.updateLink(link, repository.LinkRepository.UpdateFlag, models.AuditLinkEnabled, diff)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("[MONITOR] Lien %s modifié ou supprimé pendant la vérification, levée du signalement ignorée.", link.ShortCode)
+		return false
+	}
+	if err != nil {
+		log.Printf("[MONITOR] ERREUR lors de la levée du signalement du lien %s (%s) : %v",
+			link.ShortCode, link.LongURL, err)
+		return false
+	}
+	m.notify(link, "n'est plus signalé : destination retirée des listes de blocage ("+flagReason+")")
+	return true
+}
+
+// applyTLSResult reporte le résultat d'une vérification TLS sur le lien.
+func (m *UrlMonitor) applyTLSResult(link *models.Link, result tlsCheckResult) {
+	now := time.Now()
+	link.TLSStatus = result.Status
+	link.TLSError = result.Error
+	link.TLSExpiresAt = result.ExpiresAt
+	link.TLSCheckedAt = &now
+	link.TLSChain = result.Chain
+
+	if result.Status != models.TLSStatusValid {
+		log.Printf("[MONITOR] Certificat TLS %s pour le lien %s (%s) : %s",
+			formatTLSStatus(result.Status), link.ShortCode, link.LongURL, result.Error)
+	}
+}
+
+// updateLink enregistre avec save les colonnes du lien que le moniteur a modifiées (voir
+// LinkRepository.UpdateHealth et UpdateFlag) et, si action est renseignée et le journal d'audit configuré,
+// trace le changement dans la même transaction : un échec du journal annule la mise à jour.
+func (m *UrlMonitor) updateLink(link *models.Link, save func(repository.LinkRepository, *models.Link) error, action string, diff models.AuditDiff) error {
+	return m.linkRepo.WithinTransaction(func(tx repository.Tx) error {
+		if err := save(tx.Links, link); err != nil {
+			return err
+		}
+		if action == "" || m.audit == nil {
+			return nil
+		}
+		return m.audit.Record(tx, models.AuditEntry{
+			Actor:      monitorActor,
+			Action:     action,
+			TargetType: models.AuditTargetLink,
+			Target:     link.AuditTarget(),
+			Diff:       diff,
+		})
+	})
+}
+
+// publishHealth publie un événement link.health_changed, si la publication est configurée.
+func (m *UrlMonitor) publishHealth(link *models.Link, previousActive bool, previousTLSStatus string) {
+	if m.events == nil {
+		return
+	}
+	m.events.Publish(models.WebhookLinkHealthChanged, models.WebhookHealthData{
+		ShortCode:         link.ShortCode,
+		Domain:            link.Domain,
+		LongURL:           link.LongURL,
+		IsActive:          link.IsActive,
+		PreviousIsActive:  previousActive,
+		TLSStatus:         link.TLSStatus,
+		PreviousTLSStatus: previousTLSStatus,
+		TLSError:          link.TLSError,
+		CheckedAt:         time.Now().UTC(),
+	})
+}
+
+// notify émet une notification lorsqu'un lien change d'état.
+// C'est le point de passage unique pour toutes les notifications du moniteur.
+func (m *UrlMonitor) notify(link *models.Link, message string) {
+	log.Printf("[NOTIFICATION] Le lien %s (%s) %s", link.ShortCode, link.LongURL, message)
+}
+
+// formatTLSStatus rend le statut TLS plus lisible dans les logs.
+func formatTLSStatus(status string) string {
+	if status == "" {
+		return "NON VÉRIFIÉ"
+	}
+	return strings.ToUpper(status)
+}
+
 // formatState est une fonction utilitaire pour rendre l'état plus lisible dans les logs.
 func formatState(accessible bool) string {
 	if accessible {
@@ -0,0 +1,304 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Quanghng/url-shortener/internal/config"
+)
+
+// Erreurs de validation des destinations. Toutes enveloppent ErrDestinationNotAllowed
+// afin que les appelants puissent les détecter d'un seul errors.Is.
+var (
+	ErrDestinationNotAllowed = errors.New("destination not allowed")
+	ErrInvalidURL            = fmt.Errorf("%w: invalid URL", ErrDestinationNotAllowed)
+	ErrSchemeNotAllowed      = fmt.Errorf("%w: scheme is not allowed", ErrDestinationNotAllowed)
+	ErrDomainDenied          = fmt.Errorf("%w: domain is denied", ErrDestinationNotAllowed)
+	ErrDomainNotAllowed      = fmt.Errorf("%w: domain is not in the allow list", ErrDestinationNotAllowed)
+	ErrDomainBanned          = fmt.Errorf("%w: domain is banned", ErrDestinationNotAllowed)
+	ErrPrivateAddress        = fmt.Errorf("%w: private, loopback or link-local address", ErrDestinationNotAllowed)
+)
+
+// blockedNetworks regroupe les plages d'adresses non routables sur Internet
+// qui ne doivent jamais être contactées par le serveur (protection SSRF).
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",          // Réseau "ce réseau"
+	"10.0.0.0/8",         // RFC1918
+	"100.64.0.0/10",      // CGNAT
+	"127.0.0.0/8",        // Loopback
+	"169.254.0.0/16",     // Link-local (dont 169.254.169.254, métadonnées cloud)
+	"172.16.0.0/12",      // RFC1918
+	"192.0.0.0/24",       // Affectations protocolaires IETF
+	"192.168.0.0/16",     // RFC1918
+	"198.18.0.0/15",      // Tests de performance
+	"224.0.0.0/4",        // Multicast
+	"240.0.0.0/4",        // Réservé
+	"255.255.255.255/32", // Broadcast
+	"::/128",             // Non spécifiée
+	"::1/128",            // Loopback
+	"64:ff9b::/96",       // NAT64 (adresse IPv4 embarquée, ex. 64:ff9b::7f00:1 = 127.0.0.1)
+	"2002::/16",          // 6to4 (adresse IPv4 embarquée)
+	"fc00::/7",           // Unique local
+	"fe80::/10",          // Link-local
+	"ff00::/8",           // Multicast
+)
+
+// BanList fournit les domaines bannis dynamiquement (par la modération, en base de données).
+type BanList interface {
+	IsDomainBanned(host string) (bool, error)
+}
+
+// DestinationPolicy décide si une URL longue peut être enregistrée et contactée par le serveur.
+// Elle est utilisée à la création des liens et au moment de la connexion (dial),
+// ce qui empêche les attaques par DNS rebinding.
+type DestinationPolicy struct {
+	allowedSchemes map[string]bool
+	appSchemes     map[string]bool
+	allowedDomains []string
+	deniedDomains  []string
+	blockPrivate   bool
+	banList        BanList
+	resolver       *net.Resolver
+}
+
+// NewDestinationPolicy construit une politique à partir de la section 'security' de la configuration.
+func NewDestinationPolicy(cfg config.SecurityConfig) *DestinationPolicy {
+	schemes := make(map[string]bool, len(cfg.AllowedSchemes))
+	for _, scheme := range cfg.AllowedSchemes {
+		schemes[strings.ToLower(strings.TrimSpace(scheme))] = true
+	}
+	appSchemes := make(map[string]bool, len(cfg.AppSchemes))
+	for _, scheme := range cfg.AppSchemes {
+		appSchemes[strings.ToLower(strings.TrimSpace(scheme))] = true
+	}
+	return &DestinationPolicy{
+		allowedSchemes: schemes,
+		appSchemes:     appSchemes,
+		allowedDomains: normalizeDomains(cfg.AllowedDomains),
+		deniedDomains:  normalizeDomains(cfg.DeniedDomains),
+		blockPrivate:   cfg.BlockPrivateNetworks,
+		resolver:       net.DefaultResolver,
+	}
+}
+
+// SetBanList ajoute une source de domaines bannis consultée en plus des listes de la configuration.
+func (p *DestinationPolicy) SetBanList(banList BanList) {
+	p.banList = banList
+}
+
+// ValidateURL vérifie une URL longue au moment de la création d'un lien :
+// schéma autorisé, listes de domaines, et adresses résolues hors des plages bloquées.
+func (p *DestinationPolicy) ValidateURL(rawURL string) error {
+	u, err := p.checkURL(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if !p.blockPrivate {
+		return nil
+	}
+
+	// Adresse IP littérale : vérification directe
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		return p.CheckIP(ip)
+	}
+
+	// Nom d'hôte : on vérifie les adresses résolues. Un échec de résolution n'est pas bloquant
+	// ici, car la vérification est de toute façon refaite au moment de la connexion.
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	addrs, err := p.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if err := p.CheckIP(addr.IP); err != nil {
+			return fmt.Errorf("%w (%s resolves to %s)", err, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// ValidateAppURL vérifie une destination de règle par appareil. Les liens profonds
+// (itms-apps://, market://, intent://...) dont le schéma figure dans security.app_schemes
+// sont acceptés tels quels : ils sont ouverts par l'appareil du visiteur et jamais contactés
+// par le serveur. Les autres URLs suivent les règles de ValidateURL.
+func (p *DestinationPolicy) ValidateAppURL(rawURL string) error {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if p.appSchemes[strings.ToLower(u.Scheme)] {
+		return nil
+	}
+	return p.ValidateURL(rawURL)
+}
+
+// CheckHost vérifie un nom d'hôte par rapport aux listes d'autorisation et de refus,
+// ainsi qu'aux domaines bannis par la modération.
+func (p *DestinationPolicy) CheckHost(host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return fmt.Errorf("%w: missing host", ErrInvalidURL)
+	}
+	if matchesDomain(host, p.deniedDomains) {
+		return fmt.Errorf("%w: %s", ErrDomainDenied, host)
+	}
+	if len(p.allowedDomains) > 0 && !matchesDomain(host, p.allowedDomains) {
+		return fmt.Errorf("%w: %s", ErrDomainNotAllowed, host)
+	}
+	if p.banList != nil {
+		banned, err := p.banList.IsDomainBanned(host)
+		if err != nil {
+			return fmt.Errorf("failed to check banned domains: %w", err)
+		}
+		if banned {
+			return fmt.Errorf("%w: %s", ErrDomainBanned, host)
+		}
+	}
+	return nil
+}
+
+// CheckIP vérifie qu'une adresse IP n'appartient pas à une plage bloquée.
+func (p *DestinationPolicy) CheckIP(ip net.IP) error {
+	if !p.blockPrivate {
+		return nil
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4 // Normalise les adresses IPv4 mappées en IPv6 (::ffff:127.0.0.1)
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return fmt.Errorf("%w: %s", ErrPrivateAddress, ip)
+		}
+	}
+	return nil
+}
+
+// DialContext résout l'hôte, vérifie chaque adresse obtenue puis se connecte à l'une d'elles.
+// La connexion se fait sur l'adresse vérifiée et non sur le nom d'hôte, ce qui
+// empêche un second enregistrement DNS (rebinding) de pointer vers une adresse interne.
+func (p *DestinationPolicy) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckHost(host); err != nil {
+		return nil, err
+	}
+
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := p.resolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range addrs {
+			ips = append(ips, a.IP)
+		}
+	}
+
+	for _, ip := range ips {
+		if err := p.CheckIP(ip); err != nil {
+			return nil, fmt.Errorf("%w (%s)", err, host)
+		}
+	}
+
+	dialer := &net.Dialer{}
+	var lastErr error
+	for _, ip := range ips {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no address found for %s", host)
+	}
+	return nil, lastErr
+}
+
+// NewHTTPClient retourne un client HTTP dont toutes les connexions (redirections comprises)
+// passent par la politique de destination.
+func (p *DestinationPolicy) NewHTTPClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // Un proxy contournerait la vérification des adresses
+	transport.DialContext = p.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			_, err := p.checkURL(req.URL.String())
+			return err
+		},
+	}
+}
+
+// checkURL effectue les vérifications qui ne nécessitent pas de résolution DNS.
+func (p *DestinationPolicy) checkURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidURL, rawURL)
+	}
+	if !p.allowedSchemes[scheme] {
+		return nil, fmt.Errorf("%w: %s", ErrSchemeNotAllowed, scheme)
+	}
+	if err := p.CheckHost(u.Hostname()); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// matchesDomain indique si host correspond à l'un des domaines ou à l'un de leurs sous-domaines.
+func matchesDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDomains met les domaines en minuscules et retire les entrées vides.
+func normalizeDomains(domains []string) []string {
+	result := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain != "" {
+			result = append(result, domain)
+		}
+	}
+	return result
+}
+
+// mustParseCIDRs convertit une liste de plages CIDR, en paniquant si l'une d'elles est invalide.
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,167 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Formats d'image pris en charge
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+// Bornes et valeurs par défaut des options
+const (
+	DefaultSize   = 256  // Côté de l'image en pixels
+	MinSize       = 64   // Côté minimal en pixels
+	MaxSize       = 2048 // Côté maximal en pixels
+	DefaultMargin = 4    // Zone de silence recommandée par la norme, en modules
+	MaxMargin     = 16   // Marge maximale en modules
+)
+
+// ErrInvalidOptions est retournée lorsque le format, la taille, la marge ou le niveau de correction est invalide.
+var ErrInvalidOptions = errors.New("invalid QR code options")
+
+// eccLevels associe chaque niveau de correction d'erreur (L, M, Q, H) au niveau de la bibliothèque.
+var eccLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,     // ~7 % de la surface récupérable
+	"M": qrcode.Medium,  // ~15 %
+	"Q": qrcode.High,    // ~25 %
+	"H": qrcode.Highest, // ~30 %, à privilégier pour l'impression
+}
+
+// Options décrit l'image QR code à produire. Les champs vides prennent leur valeur par défaut.
+type Options struct {
+	Format string // png (défaut) ou svg
+	Size   int    // Côté de l'image en pixels (défaut 256)
+	Margin *int   // Zone de silence en modules (nil = 4)
+	ECC    string // Niveau de correction d'erreur : L, M (défaut), Q ou H
+}
+
+// Normalize applique les valeurs par défaut et valide les options.
+func (o Options) Normalize() (Options, error) {
+	o.Format = strings.ToLower(strings.TrimSpace(o.Format))
+	if o.Format == "" {
+		o.Format = FormatPNG
+	}
+	if o.Format != FormatPNG && o.Format != FormatSVG {
+		return o, fmt.Errorf("%w: format must be png or svg", ErrInvalidOptions)
+	}
+	if o.Size == 0 {
+		o.Size = DefaultSize
+	}
+	if o.Size < MinSize || o.Size > MaxSize {
+		return o, fmt.Errorf("%w: size must be between %d and %d pixels", ErrInvalidOptions, MinSize, MaxSize)
+	}
+	if o.Margin == nil {
+		margin := DefaultMargin
+		o.Margin = &margin
+	}
+	if *o.Margin < 0 || *o.Margin > MaxMargin {
+		return o, fmt.Errorf("%w: margin must be between 0 and %d modules", ErrInvalidOptions, MaxMargin)
+	}
+	o.ECC = strings.ToUpper(strings.TrimSpace(o.ECC))
+	if o.ECC == "" {
+		o.ECC = "M"
+	}
+	if _, ok := eccLevels[o.ECC]; !ok {
+		return o, fmt.Errorf("%w: ecc must be L, M, Q or H", ErrInvalidOptions)
+	}
+	return o, nil
+}
+
+// ContentType retourne le type MIME du format d'image.
+func ContentType(format string) string {
+	if format == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// Render encode content en QR code et retourne l'image au format demandé.
+func Render(content string, opts Options) ([]byte, error) {
+	opts, err := opts.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := qrcode.New(content, eccLevels[opts.ECC])
+	if err != nil {
+		return nil, err
+	}
+	// La marge est dessinée ici pour pouvoir être réglée (la bibliothèque impose 4 modules)
+	code.DisableBorder = true
+	modules := code.Bitmap()
+
+	// Taille d'un module en pixels ; l'image est complétée pour atteindre exactement opts.Size
+	margin := *opts.Margin
+	total := len(modules) + 2*margin
+	scale := opts.Size / total
+	if scale < 1 {
+		return nil, fmt.Errorf("%w: size %d is too small for this content (at least %d pixels)", ErrInvalidOptions, opts.Size, total)
+	}
+	offset := (opts.Size-scale*total)/2 + scale*margin
+
+	if opts.Format == FormatSVG {
+		return renderSVG(modules, opts.Size, scale, offset), nil
+	}
+	return renderPNG(modules, opts.Size, scale, offset)
+}
+
+// renderPNG dessine les modules en noir sur fond blanc dans une image en niveaux de gris.
+func renderPNG(modules [][]bool, size, scale, offset int) ([]byte, error) {
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for y, row := range modules {
+		for x, set := range row {
+			if !set {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetGray(offset+x*scale+dx, offset+y*scale+dy, color.Gray{Y: 0})
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderSVG décrit les modules par un unique chemin SVG : un rectangle par suite de modules noirs d'une ligne.
+func renderSVG(modules [][]bool, size, scale, offset int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n", size, size, size, size)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", size, size)
+	buf.WriteString(`<path fill="#000000" d="`)
+	for y, row := range modules {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv%dh-%dz", offset+start*scale, offset+y*scale, (x-start)*scale, scale, (x-start)*scale)
+		}
+	}
+	buf.WriteString(`"/>` + "\n</svg>\n")
+	return buf.Bytes()
+}
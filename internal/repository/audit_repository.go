@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditFilter restreint les entrées du journal d'audit retournées par ListAuditEntries.
+type AuditFilter struct {
+	Actor      string    // Auteur exact (vide = tous)
+	Action     string    // Action exacte, ou préfixe terminé par "." ("link." = toutes les actions sur les liens)
+	TargetType string    // Type de cible (vide = tous)
+	Target     string    // Cible exacte (vide = toutes)
+	From       time.Time // Début de la période, inclus (zéro = sans borne)
+	To         time.Time // Fin de la période, exclue (zéro = sans borne)
+	Limit      int       // Nombre maximal d'entrées (0 = sans limite)
+	Offset     int       // Nombre d'entrées ignorées (pagination)
+}
+
+// AuditRepository définit les méthodes d'accès au journal d'audit.
+// Le journal est en ajout seul : aucune méthode ne modifie ni ne supprime une entrée.
+type AuditRepository interface {
+	CreateAuditEntry(entry *models.AuditEntry) error                  // Ajouter une entrée
+	ListAuditEntries(filter AuditFilter) ([]models.AuditEntry, error) // Lister les entrées du filtre, de la plus récente à la plus ancienne
+}
+
+// GormAuditRepository est l'implémentation de AuditRepository utilisant GORM.
+type GormAuditRepository struct {
+	db *gorm.DB // Connexion à la base de données GORM
+}
+
+// NewAuditRepository crée et retourne une nouvelle instance de GormAuditRepository.
+func NewAuditRepository(db *gorm.DB) *GormAuditRepository {
+	return &GormAuditRepository{db: db}
+}
+
+// CreateAuditEntry ajoute une entrée au journal d'audit.
+func (r *GormAuditRepository) CreateAuditEntry(entry *models.AuditEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// ListAuditEntries liste les entrées du filtre, de la plus récente à la plus ancienne.
+func (r *GormAuditRepository) ListAuditEntries(filter AuditFilter) ([]models.AuditEntry, error) {
+	query := r.db.Model(&models.AuditEntry{}).Order("id DESC")
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.Action != "" {
+		if filter.Action[len(filter.Action)-1] == '.' {
+			query = query.Where("action LIKE ?", filter.Action+"%")
+		} else {
+			query = query.Where("action = ?", filter.Action)
+		}
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.Target != "" {
+		query = query.Where("target = ?", filter.Target)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var entries []models.AuditEntry
+	err := query.Find(&entries).Error
+	return entries, err
+}
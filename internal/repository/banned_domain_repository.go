@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BannedDomainRepository définit les méthodes d'accès aux domaines bannis par la modération.
+type BannedDomainRepository interface {
+	BanDomain(domain *models.BannedDomain) error       // Bannir un domaine (sans effet s'il l'est déjà)
+	IsDomainBanned(host string) (bool, error)          // Indiquer si un hôte ou l'un de ses domaines parents est banni
+	ListBannedDomains() ([]models.BannedDomain, error) // Lister les domaines bannis
+}
+
+// GormBannedDomainRepository est l'implémentation de BannedDomainRepository utilisant GORM.
+type GormBannedDomainRepository struct {
+	db *gorm.DB // Connexion à la base de données GORM
+}
+
+// NewBannedDomainRepository crée et retourne une nouvelle instance de GormBannedDomainRepository.
+func NewBannedDomainRepository(db *gorm.DB) *GormBannedDomainRepository {
+	return &GormBannedDomainRepository{db: db}
+}
+
+// BanDomain insère un domaine banni ; un domaine déjà banni est ignoré.
+func (r *GormBannedDomainRepository) BanDomain(domain *models.BannedDomain) error {
+	domain.Domain = strings.Trim(strings.ToLower(domain.Domain), ".")
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(domain).Error
+}
+
+// IsDomainBanned vérifie l'hôte et tous ses domaines parents (a.b.example.com, b.example.com, example.com).
+func (r *GormBannedDomainRepository) IsDomainBanned(host string) (bool, error) {
+	host = strings.Trim(strings.ToLower(host), ".")
+	labels := strings.Split(host, ".")
+	candidates := make([]string, 0, len(labels))
+	for i := range labels {
+		candidates = append(candidates, strings.Join(labels[i:], "."))
+	}
+
+	var count int64
+	err := r.db.Model(&models.BannedDomain{}).Where("domain IN ?", candidates).Count(&count).Error
+	return count > 0, err
+}
+
+// ListBannedDomains récupère tous les domaines bannis, du plus récent au plus ancien.
+func (r *GormBannedDomainRepository) ListBannedDomains() ([]models.BannedDomain, error) {
+	var domains []models.BannedDomain
+	err := r.db.Order("created_at DESC").Find(&domains).Error
+	return domains, err
+}
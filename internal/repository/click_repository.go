@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/Quanghng/url-shortener/internal/models"
 	"gorm.io/gorm"
@@ -11,8 +12,44 @@ import (
 // pour les opérations sur les clics. Cette abstraction permet à la couche service
 // de rester indépendante de l'implémentation spécifique de la base de données.
 type ClickRepository interface {
-	CreateClick(click *models.Click) error        // Créer un nouvel enregistrement de clic
-	CountClicksByLinkID(linkID uint) (int, error) // Compter les clics pour un lien
+	CreateClick(click *models.Click) error                                   // Créer un nouvel enregistrement de clic
+	CountClicksByLinkID(linkID uint) (int, error)                            // Compter les clics pour un lien
+	CountClicksGroupedBy(linkID uint, column string) (map[string]int, error) // Répartition des clics d'un lien selon une colonne
+	CountClicksByVariant(linkID uint) (map[string]int, error)                // Compter les clics d'un lien par variante A/B
+
+	// Statistiques agrégées sur un ensemble de liens (étiquette, campagne, dossier)
+	CountClicksForLinks(filter LinkFilter, since time.Time) (int, error)
+	ClickTimeseries(filter LinkFilter, since time.Time, interval string) ([]TimeseriesPoint, error)
+	TopLinksByClicks(filter LinkFilter, since time.Time, limit int) ([]LinkClicks, error)
+}
+
+// TimeseriesPoint est le nombre de clics d'une période (ex: "2025-06-01" pour un jour).
+type TimeseriesPoint struct {
+	Period string `json:"period"`
+	Clicks int    `json:"clicks"`
+}
+
+// LinkClicks est le nombre de clics d'un lien, utilisé pour le classement des liens les plus cliqués.
+type LinkClicks struct {
+	LinkID    uint   `json:"-"`
+	Domain    string `json:"domain"` // Domaine personnalisé du lien (vide = domaine par défaut)
+	ShortCode string `json:"short_code"`
+	LongURL   string `json:"long_url"`
+	Clicks    int    `json:"clicks"`
+}
+
+// timeseriesFormats associe chaque intervalle au format strftime (SQLite) de regroupement des clics.
+var timeseriesFormats = map[string]string{
+	"hour":  "%Y-%m-%d %H:00",
+	"day":   "%Y-%m-%d",
+	"week":  "%Y-W%W",
+	"month": "%Y-%m",
+}
+
+// IsValidTimeseriesInterval indique si l'intervalle est accepté par ClickTimeseries.
+func IsValidTimeseriesInterval(interval string) bool {
+	_, ok := timeseriesFormats[interval]
+	return ok
 }
 
 // GormClickRepository est l'implémentation de l'interface ClickRepository utilisant GORM.
@@ -46,3 +83,124 @@ func (r *GormClickRepository) CountClicksByLinkID(linkID uint) (int, error) {
 	}
 	return int(count), nil // Convertit int64 en int et retourne avec l'erreur éventuelle
 }
+
+// groupableClickColumns liste les colonnes de la table clicks autorisées pour les répartitions.
+// La colonne étant insérée dans la requête SQL, seules ces valeurs sont acceptées.
+var groupableClickColumns = map[string]bool{
+	"country":  true,
+	"platform": true,
+	"device":   true,
+	"source":   true,
+}
+
+// CountClicksGroupedBy compte les clics d'un lien regroupés par valeur d'une colonne (ex: pays).
+// Les clics sans valeur sont regroupés sous la clé "unknown".
+func (r *GormClickRepository) CountClicksGroupedBy(linkID uint, column string) (map[string]int, error) {
+	if !groupableClickColumns[column] {
+		return nil, fmt.Errorf("cannot group clicks by %q", column)
+	}
+
+	var rows []struct {
+		Value string
+		Total int
+	}
+	err := r.db.Model(&models.Click{}).
+		Select(column+" AS value, COUNT(*) AS total").
+		Where("link_id = ?", linkID).
+		Group(column).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to group clicks by %s: %w", column, err)
+	}
+
+	result := make(map[string]int, len(rows))
+	for _, row := range rows {
+		key := row.Value
+		if key == "" {
+			key = "unknown"
+		}
+		result[key] += row.Total
+	}
+	return result, nil
+}
+
+// CountClicksByVariant compte les clics d'un lien regroupés par variante du test A/B.
+// Les clics enregistrés hors test (règles de routage, lien sans variante) ne sont pas comptés.
+func (r *GormClickRepository) CountClicksByVariant(linkID uint) (map[string]int, error) {
+	var rows []struct {
+		Variant string
+		Total   int
+	}
+	err := r.db.Model(&models.Click{}).
+		Select("variant, COUNT(*) AS total").
+		Where("link_id = ? AND variant <> ''", linkID).
+		Group("variant").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count clicks by variant: %w", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Variant] = row.Total
+	}
+	return counts, nil
+}
+
+// filteredClicks prépare une requête sur les clics des liens du filtre, postérieurs à since (si non nul).
+// Les clics des liens à la corbeille sont exclus, comme ces liens le sont des listes.
+func (r *GormClickRepository) filteredClicks(filter LinkFilter, since time.Time) *gorm.DB {
+	query := r.db.Model(&models.Click{}).
+		Joins("JOIN links ON links.id = clicks.link_id AND links.deleted_at IS NULL")
+	if !filter.IsEmpty() {
+		query = query.Where("clicks.link_id IN (?)", filteredLinkIDs(r.db, filter))
+	}
+	if !since.IsZero() {
+		query = query.Where("clicks.timestamp >= ?", since)
+	}
+	return query
+}
+
+// CountClicksForLinks compte les clics de l'ensemble des liens du filtre.
+func (r *GormClickRepository) CountClicksForLinks(filter LinkFilter, since time.Time) (int, error) {
+	var count int64
+	if err := r.filteredClicks(filter, since).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count clicks: %w", err)
+	}
+	return int(count), nil
+}
+
+// ClickTimeseries compte les clics des liens du filtre par période ("hour", "day", "week" ou "month"),
+// dans l'ordre chronologique. Les périodes sans clic ne sont pas retournées.
+func (r *GormClickRepository) ClickTimeseries(filter LinkFilter, since time.Time, interval string) ([]TimeseriesPoint, error) {
+	format, ok := timeseriesFormats[interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported timeseries interval %q", interval)
+	}
+
+	var points []TimeseriesPoint
+	err := r.filteredClicks(filter, since).
+		Select("strftime(?, clicks.timestamp) AS period, COUNT(*) AS clicks", format).
+		Group("period").
+		Order("period").
+		Scan(&points).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to build click timeseries: %w", err)
+	}
+	return points, nil
+}
+
+// TopLinksByClicks retourne les liens du filtre les plus cliqués, par nombre de clics décroissant.
+func (r *GormClickRepository) TopLinksByClicks(filter LinkFilter, since time.Time, limit int) ([]LinkClicks, error) {
+	var top []LinkClicks
+	err := r.filteredClicks(filter, since).
+		Select("clicks.link_id AS link_id, links.domain AS domain, links.short_code AS short_code, links.long_url AS long_url, COUNT(*) AS clicks").
+		Group("clicks.link_id, links.domain, links.short_code, links.long_url").
+		Order("clicks DESC, clicks.link_id").
+		Limit(limit).
+		Scan(&top).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank links by clicks: %w", err)
+	}
+	return top, nil
+}
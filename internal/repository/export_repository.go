@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// ExportFilter restreint les données exportées à une période et, éventuellement, à un lien.
+// La période s'applique à la date de création des liens et à l'horodatage des clics.
+type ExportFilter struct {
+	LinkID uint      // Lien exporté (0 = tous les liens)
+	From   time.Time // Début de la période, inclus (zéro = sans borne)
+	To     time.Time // Fin de la période, exclue (zéro = sans borne)
+}
+
+// LinkExportRow est une ligne de l'export des liens : colonnes à plat, sans les règles de routage.
+type LinkExportRow struct {
+	ID         uint      `json:"id" parquet:"id"`
+	Domain     string    `json:"domain" parquet:"domain"` // Domaine personnalisé du lien (vide = domaine par défaut)
+	ShortCode  string    `json:"short_code" parquet:"short_code"`
+	LongURL    string    `json:"long_url" parquet:"long_url"`
+	CreatedAt  time.Time `json:"created_at" parquet:"created_at"`
+	IsActive   bool      `json:"is_active" parquet:"is_active"`
+	IsDisabled bool      `json:"is_disabled" parquet:"is_disabled"`
+	MaxClicks  int       `json:"max_clicks" parquet:"max_clicks"`
+	ClickCount int       `json:"click_count" parquet:"click_count"`
+	Campaign   string    `json:"campaign" parquet:"campaign"`
+	Folder     string    `json:"folder" parquet:"folder"`
+	Tags       string    `json:"tags" parquet:"tags"` // Noms des étiquettes séparés par ";"
+}
+
+// ClickExportRow est une ligne de l'export des clics, avec le domaine et le code court du lien cliqué.
+type ClickExportRow struct {
+	ID        uint      `json:"id" parquet:"id"`
+	LinkID    uint      `json:"link_id" parquet:"link_id"`
+	Domain    string    `json:"domain" parquet:"domain"`
+	ShortCode string    `json:"short_code" parquet:"short_code"`
+	Timestamp time.Time `json:"timestamp" parquet:"timestamp"`
+	Country   string    `json:"country" parquet:"country"`
+	Platform  string    `json:"platform" parquet:"platform"`
+	Device    string    `json:"device" parquet:"device"`
+	Variant   string    `json:"variant" parquet:"variant"`
+	Source    string    `json:"source" parquet:"source"`
+	UserAgent string    `json:"user_agent" parquet:"user_agent"`
+	IPAddress string    `json:"ip_address" parquet:"ip_address"`
+}
+
+// ExportRepository parcourt les liens et les clics ligne par ligne, sans charger
+// l'ensemble des résultats en mémoire.
+type ExportRepository interface {
+	EachLink(filter ExportFilter, fn func(row *LinkExportRow) error) error
+	EachClick(filter ExportFilter, fn func(row *ClickExportRow) error) error
+}
+
+// GormExportRepository est l'implémentation de ExportRepository utilisant GORM.
+type GormExportRepository struct {
+	db *gorm.DB
+}
+
+// NewExportRepository crée et retourne une nouvelle instance de GormExportRepository.
+func NewExportRepository(db *gorm.DB) *GormExportRepository {
+	return &GormExportRepository{db: db}
+}
+
+// EachLink appelle fn pour chaque lien du filtre, par identifiant croissant.
+// Les étiquettes sont concaténées par SQLite (group_concat) pour rester sur une seule requête.
+func (r *GormExportRepository) EachLink(filter ExportFilter, fn func(row *LinkExportRow) error) error {
+	query := r.db.Model(&models.Link{}).
+		Select(`links.id, links.domain, links.short_code, links.long_url, links.created_at, links.is_active, links.is_disabled,
+			links.max_clicks, links.click_count, COALESCE(campaigns.name, '') AS campaign, links.folder,
+			COALESCE((SELECT group_concat(tags.name, ';') FROM link_tags JOIN tags ON tags.id = link_tags.tag_id
+				WHERE link_tags.link_id = links.id), '') AS tags`).
+		Joins("LEFT JOIN campaigns ON campaigns.id = links.campaign_id").
+		Order("links.id")
+	query = applyExportFilter(query, filter, "links.id", "links.created_at")
+
+	rows, err := query.Rows()
+	if err != nil {
+		return fmt.Errorf("failed to export links: %w", err)
+	}
+	return eachRow(r.db, rows, fn)
+}
+
+// EachClick appelle fn pour chaque clic du filtre, par identifiant croissant.
+func (r *GormExportRepository) EachClick(filter ExportFilter, fn func(row *ClickExportRow) error) error {
+	query := r.db.Model(&models.Click{}).
+		Select(`clicks.id, clicks.link_id, links.domain, links.short_code, clicks.timestamp, clicks.country, clicks.platform,
+			clicks.device, clicks.variant, clicks.source, clicks.user_agent, clicks.ip_address`).
+		Joins("JOIN links ON links.id = clicks.link_id AND links.deleted_at IS NULL"). // Liens à la corbeille exclus
+		Order("clicks.id")
+	query = applyExportFilter(query, filter, "clicks.link_id", "clicks.timestamp")
+
+	rows, err := query.Rows()
+	if err != nil {
+		return fmt.Errorf("failed to export clicks: %w", err)
+	}
+	return eachRow(r.db, rows, fn)
+}
+
+// applyExportFilter ajoute les conditions du filtre ; linkColumn et timeColumn désignent
+// les colonnes portant l'identifiant du lien et la date de la ligne exportée.
+func applyExportFilter(query *gorm.DB, filter ExportFilter, linkColumn, timeColumn string) *gorm.DB {
+	if filter.LinkID != 0 {
+		query = query.Where(linkColumn+" = ?", filter.LinkID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where(timeColumn+" >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where(timeColumn+" < ?", filter.To)
+	}
+	return query
+}
+
+// eachRow lit les lignes une à une et passe chacune à fn.
+// Le parcours s'arrête à la première erreur retournée par fn.
+func eachRow[T any](db *gorm.DB, rows *sql.Rows, fn func(row *T) error) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		var row T
+		if err := db.ScanRows(rows, &row); err != nil {
+			return fmt.Errorf("failed to scan export row: %w", err)
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// LinkFilter restreint un ensemble de liens par étiquette, campagne ou dossier.
+// Les critères vides sont ignorés ; les critères renseignés se cumulent.
+type LinkFilter struct {
+	Tag      string // Nom d'étiquette
+	Campaign string // Nom de campagne
+	Folder   string // Dossier, sous-dossiers inclus
+	Limit    int    // Nombre maximal de liens retournés par ListLinks (0 = pas de limite)
+	Offset   int    // Décalage pour la pagination de ListLinks
+}
+
+// IsEmpty indique si le filtre ne contient aucun critère de sélection.
+func (f LinkFilter) IsEmpty() bool {
+	return f.Tag == "" && f.Campaign == "" && f.Folder == ""
+}
+
+// applyLinkFilter ajoute à une requête sur la table 'links' les conditions du filtre.
+func applyLinkFilter(db *gorm.DB, filter LinkFilter) *gorm.DB {
+	if filter.Tag != "" {
+		db = db.Where("links.id IN (?)", db.Session(&gorm.Session{NewDB: true}).
+			Table("link_tags").
+			Select("link_tags.link_id").
+			Joins("JOIN tags ON tags.id = link_tags.tag_id").
+			Where("tags.name = ?", filter.Tag))
+	}
+	if filter.Campaign != "" {
+		db = db.Where("links.campaign_id IN (?)", db.Session(&gorm.Session{NewDB: true}).
+			Model(&models.Campaign{}).
+			Select("id").
+			Where("name = ?", filter.Campaign))
+	}
+	if filter.Folder != "" {
+		db = db.Where("(links.folder = ? OR links.folder LIKE ? ESCAPE '\\')", filter.Folder, escapeLike(filter.Folder)+"/%")
+	}
+	return db
+}
+
+// filteredLinkIDs retourne une sous-requête sélectionnant les identifiants des liens du filtre.
+func filteredLinkIDs(db *gorm.DB, filter LinkFilter) *gorm.DB {
+	return applyLinkFilter(db.Session(&gorm.Session{NewDB: true}).Model(&models.Link{}).Select("links.id"), filter)
+}
+
+// escapeLike échappe les caractères spéciaux d'un motif LIKE.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
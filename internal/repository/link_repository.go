@@ -1,18 +1,43 @@
 package repository
 
 import (
+	"net/url"
+	"strings"
+	"time"
+
 	"github.com/Quanghng/url-shortener/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // LinkRepository est une interface qui définit les méthodes d'accès aux données
 // pour les opérations CRUD sur les liens.
 type LinkRepository interface {
-	CreateLink(link *models.Link) error                        // Créer un nouveau lien
-	GetLinkByShortCode(shortCode string) (*models.Link, error) // Récupérer un lien par son code court
-	GetAllLinks() ([]models.Link, error)                       // Récupérer tous les liens
-	CountClicksByLinkID(linkID uint) (int, error)              // Compter les clics pour un lien
-	UpdateLink(link *models.Link) error                        // Mettre à jour un lien (pour le moniteur)
+	CreateLink(link *models.Link) error                                  // Créer un nouveau lien
+	GetLinkByShortCode(domain, shortCode string) (*models.Link, error)   // Récupérer un lien par son domaine et son code court
+	GetAllLinks() ([]models.Link, error)                                 // Récupérer tous les liens
+	ListLinksByDestinationHost(domain string) ([]models.Link, error)     // Lister les liens dont la destination est sur un domaine (sous-domaines compris)
+	ListLinks(filter LinkFilter) ([]models.Link, error)                  // Lister les liens d'une étiquette, d'une campagne ou d'un dossier
+	CountClicksByLinkID(linkID uint) (int, error)                        // Compter les clics pour un lien
+	UpdateHealth(link *models.Link) error                                // Enregistrer l'accessibilité et la vérification TLS (moniteur)
+	UpdateFlag(link *models.Link) error                                  // Enregistrer (ou lever) le signalement de réputation et la désactivation qui l'accompagne
+	UpdateDisabled(link *models.Link) error                              // Enregistrer la désactivation (ou réactivation) d'un lien
+	ReserveClick(linkID uint) (bool, error)                              // Consommer atomiquement une redirection d'un lien limité
+	UpdateMetadata(linkID uint, meta models.LinkMetadata) error          // Enregistrer les métadonnées de la destination d'un lien
+	NextSequenceValue(name string) (uint64, error)                       // Incrémenter une séquence persistante (codes séquentiels)
+	FindLinksByURLHash(domain, urlHash string) ([]models.Link, error)    // Lister les liens d'un domaine vers une même URL canonique
+	UpdateDestination(link *models.Link, rev *models.LinkRevision) error // Changer la destination d'un lien et tracer la révision
+	ListRevisions(linkID uint) ([]models.LinkRevision, error)            // Lister l'historique des destinations d'un lien
+	ShortCodeExists(domain, shortCode string) (bool, error)              // Indiquer si un code court est pris (liens à la corbeille compris)
+	DeleteLink(link *models.Link, actor string) error                    // Mettre un lien à la corbeille
+	GetDeletedLink(domain, shortCode string) (*models.Link, error)       // Récupérer un lien à la corbeille
+	ListDeletedLinks(filter LinkFilter) ([]models.Link, error)           // Lister les liens à la corbeille
+	RestoreLink(linkID uint) error                                       // Sortir un lien de la corbeille
+	PurgeDeletedLinks(before time.Time) ([]models.Link, error)           // Supprimer définitivement les liens mis à la corbeille avant une date
+
+	// Exécuter fn dans une transaction : les dépôts fournis (Tx) écrivent dans la transaction,
+	// annulée si fn retourne une erreur
+	WithinTransaction(fn func(tx Tx) error) error
 }
 
 // GormLinkRepository est l'implémentation de LinkRepository utilisant GORM.
@@ -32,12 +57,13 @@ func (r *GormLinkRepository) CreateLink(link *models.Link) error {
 	return r.db.Create(link).Error
 }
 
-// GetLinkByShortCode récupère un lien de la base de données en utilisant son shortCode.
-// Il renvoie gorm.ErrRecordNotFound si aucun lien n'est trouvé avec ce shortCode.
-func (r *GormLinkRepository) GetLinkByShortCode(shortCode string) (*models.Link, error) {
+// GetLinkByShortCode récupère un lien de la base de données en utilisant son domaine et son shortCode
+// (domaine vide = domaine par défaut).
+// Il renvoie gorm.ErrRecordNotFound si aucun lien n'est trouvé avec ce shortCode sur ce domaine.
+func (r *GormLinkRepository) GetLinkByShortCode(domain, shortCode string) (*models.Link, error) {
 	var link models.Link
-	// First trouve le premier enregistrement où ShortCode = shortCode
-	err := r.db.Where("short_code = ?", shortCode).First(&link).Error
+	// First trouve le premier enregistrement où Domain = domain et ShortCode = shortCode
+	err := r.db.Where("domain = ? AND short_code = ?", domain, shortCode).First(&link).Error
 	return &link, err
 }
 
@@ -50,6 +76,54 @@ func (r *GormLinkRepository) GetAllLinks() ([]models.Link, error) {
 	return links, err
 }
 
+// ListLinksByDestinationHost récupère les liens dont l'URL de destination a pour hôte domain
+// ou l'un de ses sous-domaines. La requête présélectionne les URLs contenant le domaine ;
+// l'hôte de chaque destination est ensuite vérifié.
+func (r *GormLinkRepository) ListLinksByDestinationHost(domain string) ([]models.Link, error) {
+	domain = strings.Trim(strings.ToLower(domain), ".")
+	var candidates []models.Link
+	if err := r.db.Where("LOWER(long_url) LIKE ?", "%"+domain+"%").Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	var links []models.Link
+	for _, link := range candidates {
+		u, err := url.Parse(link.LongURL)
+		if err != nil {
+			continue
+		}
+		host := strings.Trim(strings.ToLower(u.Hostname()), ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+// ListLinks liste les liens correspondant au filtre, du plus récent au plus ancien,
+// avec leurs étiquettes et leur campagne.
+func (r *GormLinkRepository) ListLinks(filter LinkFilter) ([]models.Link, error) {
+	var links []models.Link
+	query := applyLinkFilter(r.db.Model(&models.Link{}), filter).
+		Preload("Tags").
+		Preload("Campaign").
+		Order("links.id DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+	err := query.Find(&links).Error
+	return links, err
+}
+
+// WithinTransaction exécute fn dans une transaction de base de données.
+// Les dépôts passés à fn partagent la transaction ; elle est annulée si fn retourne une erreur.
+func (r *GormLinkRepository) WithinTransaction(fn func(tx Tx) error) error {
+	return withinTransaction(r.db, fn)
+}
+
 // CountClicksByLinkID compte le nombre total de clics pour un ID de lien donné.
 func (r *GormLinkRepository) CountClicksByLinkID(linkID uint) (int, error) {
 	var count int64 // GORM retourne un int64 pour les comptes
@@ -58,9 +132,252 @@ func (r *GormLinkRepository) CountClicksByLinkID(linkID uint) (int, error) {
 	return int(count), err
 }
 
-// UpdateLink met à jour un lien existant dans la base de données.
-// Utilisé principalement par le moniteur pour mettre à jour le statut IsActive.
-func (r *GormLinkRepository) UpdateLink(link *models.Link) error {
-	// Save met à jour tous les champs du lien dans la base de données
-	return r.db.Save(link).Error
+// Colonnes écrites par UpdateHealth et UpdateFlag. Comme UpdateDisabled pour la modération,
+// le moniteur et la vérification de réputation n'écrivent que les leurs : un lien chargé avant
+// une vérification lente n'écrase pas ce que les autres ont modifié entre-temps.
+var (
+	healthColumns = []string{"is_active", "tls_status", "tls_error", "tls_expires_at", "tls_checked_at", "tls_chain"}
+	flagColumns   = []string{"flagged_at", "flag_reason", "is_disabled", "disabled_reason"}
+)
+
+// UpdateHealth enregistre l'accessibilité et le résultat de la vérification TLS d'un lien.
+// Retourne gorm.ErrRecordNotFound si le lien a été mis à la corbeille ou si sa destination
+// a changé depuis la vérification (le résultat porte alors sur l'ancienne destination).
+func (r *GormLinkRepository) UpdateHealth(link *models.Link) error {
+	return updateColumns(r.db.Where("long_url = ?", link.LongURL), link, healthColumns...)
+}
+
+// UpdateFlag enregistre le signalement de réputation d'un lien et sa désactivation (ou leur levée).
+// Comme UpdateHealth, il ne s'applique qu'à la destination vérifiée.
+func (r *GormLinkRepository) UpdateFlag(link *models.Link) error {
+	return updateColumns(r.db.Where("long_url = ?", link.LongURL), link, flagColumns...)
+}
+
+// UpdateDisabled enregistre la désactivation d'un lien et sa raison (ou sa réactivation),
+// sans écraser les autres colonnes du lien modifiées entre-temps (moniteur, destination).
+// Retourne gorm.ErrRecordNotFound si le lien a été mis à la corbeille.
+func (r *GormLinkRepository) UpdateDisabled(link *models.Link) error {
+	return updateColumns(r.db, link, "is_disabled", "disabled_reason")
+}
+
+// updateColumns écrit les colonnes du lien, s'il existe encore hors corbeille (et satisfait
+// les conditions de db) ; sinon retourne gorm.ErrRecordNotFound.
+func updateColumns(db *gorm.DB, link *models.Link, columns ...string) error {
+	result := db.Model(link).Select(columns).Updates(link)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ReserveClick incrémente atomiquement le compteur de redirections d'un lien limité,
+// uniquement si la limite n'est pas atteinte. La condition est évaluée par la base de données
+// dans la même requête UPDATE, ce qui empêche des redirections concurrentes de dépasser MaxClicks.
+// Retourne false si la limite était déjà atteinte.
+func (r *GormLinkRepository) ReserveClick(linkID uint) (bool, error) {
+	result := r.db.Model(&models.Link{}).
+		Where("id = ? AND max_clicks > 0 AND click_count < max_clicks", linkID).
+		UpdateColumn("click_count", gorm.Expr("click_count + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateMetadata enregistre les métadonnées de la destination d'un lien.
+// Seules les colonnes meta_* sont écrites, afin de ne pas écraser une modification concurrente du lien.
+func (r *GormLinkRepository) UpdateMetadata(linkID uint, meta models.LinkMetadata) error {
+	return r.db.Model(&models.Link{}).Where("id = ?", linkID).Updates(map[string]interface{}{
+		"meta_title":       meta.Title,
+		"meta_description": meta.Description,
+		"meta_favicon_url": meta.FaviconURL,
+		"meta_image_url":   meta.ImageURL,
+		"meta_error":       meta.Error,
+		"meta_fetched_at":  meta.FetchedAt,
+	}).Error
+}
+
+// NextSequenceValue incrémente la séquence nommée (créée à 1 si elle n'existe pas) et retourne sa valeur.
+// L'incrément est fait par la base de données (INSERT ... ON CONFLICT DO UPDATE) : deux appels concurrents
+// obtiennent deux valeurs distinctes. Dans une transaction (WithinTransaction), l'incrément en fait partie.
+func (r *GormLinkRepository) NextSequenceValue(name string) (uint64, error) {
+	var seq models.Sequence
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"value": gorm.Expr("value + 1")}),
+		}).Create(&models.Sequence{Name: name, Value: 1}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("name = ?", name).First(&seq).Error
+	})
+	return seq.Value, err
+}
+
+// FindLinksByURLHash liste, du plus ancien au plus récent, les liens du domaine dont l'URL longue
+// canonique a l'empreinte urlHash (index sur url_hash), avec leurs étiquettes.
+func (r *GormLinkRepository) FindLinksByURLHash(domain, urlHash string) ([]models.Link, error) {
+	var links []models.Link
+	err := r.db.Preload("Tags").
+		Where("domain = ? AND url_hash = ?", domain, urlHash).
+		Order("id ASC").
+		Find(&links).Error
+	return links, err
+}
+
+// flagDisabledPattern reconnaît la raison de désactivation d'un lien signalé par la vérification de réputation.
+const flagDisabledPattern = models.ReputationDisabledPrefix + "%"
+
+// UpdateDestination remplace l'URL longue d'un lien et enregistre la révision correspondante
+// dans une même transaction. Le numéro de révision (rev.Revision) est attribué ici, à la suite
+// des révisions existantes du lien. L'état de santé établi pour l'ancienne destination (accessibilité,
+// vérification TLS, signalement de réputation et désactivation qui l'accompagne) est réinitialisé :
+// le moniteur vérifiera la nouvelle. Une désactivation de modération est conservée.
+func (r *GormLinkRepository) UpdateDestination(link *models.Link, rev *models.LinkRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var last int
+		err := tx.Model(&models.LinkRevision{}).
+			Where("link_id = ?", link.ID).
+			Select("COALESCE(MAX(revision), 0)").
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+		rev.LinkID = link.ID
+		rev.Revision = last + 1
+		if err := tx.Create(rev).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&models.Link{}).Where("id = ?", link.ID).Updates(map[string]interface{}{
+			"long_url":       link.LongURL,
+			"url_hash":       link.URLHash,
+			"is_active":      true,
+			"tls_status":     "",
+			"tls_error":      "",
+			"tls_expires_at": nil,
+			"tls_checked_at": nil,
+			"tls_chain":      nil,
+			"flagged_at":     nil,
+			"flag_reason":    "",
+			// Évalués sur la ligne avant mise à jour : seule la désactivation due au signalement est levée
+			"is_disabled":     gorm.Expr("CASE WHEN flagged_at IS NOT NULL AND disabled_reason LIKE ? THEN ? ELSE is_disabled END", flagDisabledPattern, false),
+			"disabled_reason": gorm.Expr("CASE WHEN flagged_at IS NOT NULL AND disabled_reason LIKE ? THEN '' ELSE disabled_reason END", flagDisabledPattern),
+		}).Error
+		if err != nil {
+			return err
+		}
+		link.IsActive = true
+		link.TLSStatus, link.TLSError = "", ""
+		link.TLSExpiresAt, link.TLSCheckedAt, link.TLSChain = nil, nil, nil
+		link.ClearFlag()
+		return nil
+	})
+}
+
+// ListRevisions liste les révisions de la destination d'un lien, de la plus ancienne à la plus récente.
+func (r *GormLinkRepository) ListRevisions(linkID uint) ([]models.LinkRevision, error) {
+	var revisions []models.LinkRevision
+	err := r.db.Where("link_id = ?", linkID).Order("revision ASC").Find(&revisions).Error
+	return revisions, err
+}
+
+// ShortCodeExists indique si le code court est déjà attribué sur le domaine, y compris à un lien
+// à la corbeille : un code n'est réattribué qu'après la purge définitive du lien.
+func (r *GormLinkRepository) ShortCodeExists(domain, shortCode string) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Link{}).
+		Where("domain = ? AND short_code = ?", domain, shortCode).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteLink met un lien à la corbeille (suppression logique) en enregistrant l'auteur de la suppression.
+func (r *GormLinkRepository) DeleteLink(link *models.Link, actor string) error {
+	now := time.Now()
+	err := r.db.Model(&models.Link{}).Where("id = ?", link.ID).Updates(map[string]interface{}{
+		"deleted_at": now,
+		"deleted_by": actor,
+	}).Error
+	if err != nil {
+		return err
+	}
+	link.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	link.DeletedBy = actor
+	return nil
+}
+
+// GetDeletedLink récupère un lien à la corbeille par son domaine et son code court.
+// Il renvoie gorm.ErrRecordNotFound si aucun lien supprimé ne porte ce code.
+func (r *GormLinkRepository) GetDeletedLink(domain, shortCode string) (*models.Link, error) {
+	var link models.Link
+	err := r.db.Unscoped().
+		Where("domain = ? AND short_code = ? AND deleted_at IS NOT NULL", domain, shortCode).
+		First(&link).Error
+	return &link, err
+}
+
+// ListDeletedLinks liste les liens à la corbeille correspondant au filtre,
+// du plus récemment supprimé au plus ancien, avec leurs étiquettes et leur campagne.
+func (r *GormLinkRepository) ListDeletedLinks(filter LinkFilter) ([]models.Link, error) {
+	var links []models.Link
+	query := applyLinkFilter(r.db.Unscoped().Model(&models.Link{}), filter).
+		Where("links.deleted_at IS NOT NULL").
+		Preload("Tags").
+		Preload("Campaign").
+		Order("links.deleted_at DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+	err := query.Find(&links).Error
+	return links, err
+}
+
+// RestoreLink sort un lien de la corbeille.
+func (r *GormLinkRepository) RestoreLink(linkID uint) error {
+	return r.db.Unscoped().Model(&models.Link{}).Where("id = ?", linkID).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": "",
+	}).Error
+}
+
+// PurgeDeletedLinks supprime définitivement les liens mis à la corbeille avant la date before,
+// avec leurs clics, leurs associations aux étiquettes et l'historique de leur destination.
+// Les signalements, actions de modération et entrées d'audit sont conservés.
+// Retourne les liens purgés (identifiant, domaine, code court, destination et date de suppression).
+func (r *GormLinkRepository) PurgeDeletedLinks(before time.Time) ([]models.Link, error) {
+	var links []models.Link
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Select("id", "domain", "short_code", "long_url", "deleted_at", "deleted_by").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Find(&links).Error
+		if err != nil || len(links) == 0 {
+			return err
+		}
+		ids := make([]uint, len(links))
+		for i, link := range links {
+			ids[i] = link.ID
+		}
+
+		if err := tx.Where("link_id IN ?", ids).Delete(&models.Click{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("link_id IN ?", ids).Delete(&models.LinkRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM link_tags WHERE link_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&models.Link{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
 }
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/urlnorm"
+	"gorm.io/gorm"
+)
+
+// AutoMigrate crée ou met à jour toutes les tables de l'application à partir des modèles GORM.
+// C'est le point unique utilisé par la commande 'migrate' et par les commandes CLI.
+func AutoMigrate(db *gorm.DB) error {
+	err := db.AutoMigrate(
+		&models.Tag{},
+		&models.Campaign{},
+		&models.Link{},
+		&models.Click{},
+		&models.Report{},
+		&models.ModerationAction{},
+		&models.BannedDomain{},
+		&models.Sequence{},
+		&models.LinkRevision{},
+		&models.AuditEntry{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := backfillURLHashes(db); err != nil {
+		return err
+	}
+	if err := protectAuditEntries(db); err != nil {
+		return err
+	}
+
+	// Les codes courts étaient uniques sur toute la table ; ils ne le sont plus que par domaine
+	// (index idx_links_domain_code). AutoMigrate ne supprimant pas les index, l'ancien est retiré ici.
+	if db.Migrator().HasIndex(&models.Link{}, "idx_links_short_code") {
+		return db.Migrator().DropIndex(&models.Link{}, "idx_links_short_code")
+	}
+	return nil
+}
+
+// auditEntriesTriggers rendent la table audit_entries en ajout seul au niveau du schéma (SQLite) :
+// toute modification ou suppression d'une entrée est refusée, quel que soit le client de la base.
+var auditEntriesTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS audit_entries_no_update BEFORE UPDATE ON audit_entries
+	BEGIN SELECT RAISE(ABORT, 'audit_entries is append-only'); END`,
+	`CREATE TRIGGER IF NOT EXISTS audit_entries_no_delete BEFORE DELETE ON audit_entries
+	BEGIN SELECT RAISE(ABORT, 'audit_entries is append-only'); END`,
+}
+
+// protectAuditEntries crée les déclencheurs qui interdisent de modifier ou supprimer le journal d'audit.
+func protectAuditEntries(db *gorm.DB) error {
+	for _, stmt := range auditEntriesTriggers {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to protect audit_entries: %w", err)
+		}
+	}
+	return nil
+}
+
+// backfillURLHashes calcule l'empreinte de l'URL canonique des liens créés avant la déduplication.
+// Une URL impossible à normaliser garde une empreinte vide et n'est jamais dédupliquée.
+func backfillURLHashes(db *gorm.DB) error {
+	var links []models.Link
+	return db.Select("id", "long_url").Where("url_hash = '' OR url_hash IS NULL").
+		FindInBatches(&links, 500, func(tx *gorm.DB, batch int) error {
+			for _, link := range links {
+				hash, err := urlnorm.Hash(link.LongURL)
+				if err != nil {
+					continue
+				}
+				if err := tx.Model(&models.Link{}).Where("id = ?", link.ID).Update("url_hash", hash).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"github.com/Quanghng/url-shortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// ReportRepository définit les méthodes d'accès aux données des signalements d'abus
+// et des actions de modération associées.
+type ReportRepository interface {
+	CreateReport(report *models.Report) error                               // Enregistrer un signalement
+	GetReportByID(id uint) (*models.Report, error)                          // Récupérer un signalement (avec son lien)
+	ListReports(status string) ([]models.Report, error)                     // Lister les signalements, filtrés par statut si non vide
+	UpdateReport(report *models.Report) error                               // Mettre à jour un signalement (statut)
+	CreateModerationAction(action *models.ModerationAction) error           // Tracer une action de modération
+	ListModerationActions(reportID uint) ([]models.ModerationAction, error) // Historique des actions d'un signalement
+}
+
+// GormReportRepository est l'implémentation de ReportRepository utilisant GORM.
+type GormReportRepository struct {
+	db *gorm.DB // Connexion à la base de données GORM
+}
+
+// NewReportRepository crée et retourne une nouvelle instance de GormReportRepository.
+func NewReportRepository(db *gorm.DB) *GormReportRepository {
+	return &GormReportRepository{db: db}
+}
+
+// CreateReport insère un nouveau signalement dans la base de données.
+func (r *GormReportRepository) CreateReport(report *models.Report) error {
+	return r.db.Create(report).Error
+}
+
+// GetReportByID récupère un signalement et son lien.
+// Il renvoie gorm.ErrRecordNotFound si aucun signalement n'existe avec cet ID.
+func (r *GormReportRepository) GetReportByID(id uint) (*models.Report, error) {
+	var report models.Report
+	err := r.db.Preload("Link").First(&report, id).Error
+	return &report, err
+}
+
+// ListReports récupère les signalements du plus récent au plus ancien.
+func (r *GormReportRepository) ListReports(status string) ([]models.Report, error) {
+	var reports []models.Report
+	query := r.db.Preload("Link").Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Find(&reports).Error
+	return reports, err
+}
+
+// UpdateReport met à jour un signalement existant.
+func (r *GormReportRepository) UpdateReport(report *models.Report) error {
+	// Omit évite de réécrire le lien associé
+	return r.db.Omit("Link").Save(report).Error
+}
+
+// CreateModerationAction insère une action de modération (append-only).
+func (r *GormReportRepository) CreateModerationAction(action *models.ModerationAction) error {
+	return r.db.Create(action).Error
+}
+
+// ListModerationActions récupère les actions d'un signalement dans l'ordre chronologique.
+func (r *GormReportRepository) ListModerationActions(reportID uint) ([]models.ModerationAction, error) {
+	var actions []models.ModerationAction
+	err := r.db.Where("report_id = ?", reportID).Order("created_at ASC").Find(&actions).Error
+	return actions, err
+}
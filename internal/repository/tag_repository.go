@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"github.com/Quanghng/url-shortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// TagRepository définit l'accès aux étiquettes et aux campagnes des liens.
+type TagRepository interface {
+	FindOrCreateTags(names []string) ([]models.Tag, error)      // Retourne les étiquettes, en créant celles qui n'existent pas
+	FindOrCreateCampaign(name string) (*models.Campaign, error) // Retourne la campagne, en la créant si besoin
+	ListTags() ([]models.Tag, error)                            // Liste les étiquettes par ordre alphabétique
+	ListCampaigns() ([]models.Campaign, error)                  // Liste les campagnes par ordre alphabétique
+}
+
+// GormTagRepository est l'implémentation de TagRepository utilisant GORM.
+type GormTagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository crée et retourne une nouvelle instance de GormTagRepository.
+func NewTagRepository(db *gorm.DB) *GormTagRepository {
+	return &GormTagRepository{db: db}
+}
+
+// FindOrCreateTags retourne les étiquettes demandées, dans l'ordre, en créant celles qui n'existent pas.
+func (r *GormTagRepository) FindOrCreateTags(names []string) ([]models.Tag, error) {
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		var tag models.Tag
+		if err := r.db.Where(models.Tag{Name: name}).FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// FindOrCreateCampaign retourne la campagne portant ce nom, en la créant si elle n'existe pas.
+func (r *GormTagRepository) FindOrCreateCampaign(name string) (*models.Campaign, error) {
+	var campaign models.Campaign
+	if err := r.db.Where(models.Campaign{Name: name}).FirstOrCreate(&campaign).Error; err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// ListTags liste les étiquettes par ordre alphabétique.
+func (r *GormTagRepository) ListTags() ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.Order("name").Find(&tags).Error
+	return tags, err
+}
+
+// ListCampaigns liste les campagnes par ordre alphabétique.
+func (r *GormTagRepository) ListCampaigns() ([]models.Campaign, error) {
+	var campaigns []models.Campaign
+	err := r.db.Order("name").Find(&campaigns).Error
+	return campaigns, err
+}
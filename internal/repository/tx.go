@@ -0,0 +1,29 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Tx regroupe les dépôts d'une transaction ouverte par WithinTransaction.
+// Leurs écritures sont validées ensemble, ou annulées ensemble si la fonction retourne une erreur :
+// une action d'administration et son entrée d'audit sont ainsi enregistrées atomiquement.
+type Tx struct {
+	Links         LinkRepository
+	Tags          TagRepository
+	Reports       ReportRepository
+	BannedDomains BannedDomainRepository
+	Webhooks      WebhookRepository
+	Audit         AuditRepository
+}
+
+// withinTransaction exécute fn dans une transaction de db avec des dépôts qui y écrivent.
+func withinTransaction(db *gorm.DB, fn func(tx Tx) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		return fn(Tx{
+			Links:         NewLinkRepository(tx),
+			Tags:          NewTagRepository(tx),
+			Reports:       NewReportRepository(tx),
+			BannedDomains: NewBannedDomainRepository(tx),
+			Webhooks:      NewWebhookRepository(tx),
+			Audit:         NewAuditRepository(tx),
+		})
+	})
+}
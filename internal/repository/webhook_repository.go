@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"gorm.io/gorm"
+)
+
+// DeliveryFilter restreint le journal des livraisons retourné par ListDeliveries.
+type DeliveryFilter struct {
+	SubscriptionID uint   // Abonnement (0 = tous)
+	Status         string // Statut (vide = tous)
+	Limit          int    // Nombre maximal de livraisons (0 = sans limite)
+	Offset         int    // Nombre de livraisons ignorées (pagination)
+}
+
+// WebhookRepository définit les méthodes d'accès aux abonnements webhook et à leurs livraisons.
+type WebhookRepository interface {
+	CreateSubscription(sub *models.WebhookSubscription) error                // Créer un abonnement
+	GetSubscription(id uint) (*models.WebhookSubscription, error)            // Récupérer un abonnement
+	ListSubscriptions() ([]models.WebhookSubscription, error)                // Lister les abonnements
+	DeleteSubscription(id uint) error                                        // Supprimer un abonnement (ses livraisons sont conservées)
+	CreateDelivery(delivery *models.WebhookDelivery) error                   // Enregistrer une livraison à effectuer
+	GetDelivery(id uint) (*models.WebhookDelivery, error)                    // Récupérer une livraison
+	ListDeliveries(filter DeliveryFilter) ([]models.WebhookDelivery, error)  // Lister les livraisons, de la plus récente à la plus ancienne
+	ListDueDeliveries(now time.Time, limit int) ([]uint, error)              // Lister les livraisons en attente dont la tentative est due
+	ClaimDelivery(id uint, now time.Time, lease time.Duration) (bool, error) // Réserver une livraison due pour une tentative
+	UpdateDelivery(delivery *models.WebhookDelivery) error                   // Enregistrer le résultat d'une tentative
+
+	// Exécuter fn dans une transaction : les dépôts fournis (Tx) écrivent dans la transaction,
+	// annulée si fn retourne une erreur
+	WithinTransaction(fn func(tx Tx) error) error
+}
+
+// GormWebhookRepository est l'implémentation de WebhookRepository utilisant GORM.
+type GormWebhookRepository struct {
+	db *gorm.DB // Connexion à la base de données GORM
+}
+
+// NewWebhookRepository crée et retourne une nouvelle instance de GormWebhookRepository.
+func NewWebhookRepository(db *gorm.DB) *GormWebhookRepository {
+	return &GormWebhookRepository{db: db}
+}
+
+// CreateSubscription insère un nouvel abonnement.
+func (r *GormWebhookRepository) CreateSubscription(sub *models.WebhookSubscription) error {
+	return r.db.Create(sub).Error
+}
+
+// GetSubscription récupère un abonnement par son identifiant.
+// Il renvoie gorm.ErrRecordNotFound si l'abonnement n'existe pas.
+func (r *GormWebhookRepository) GetSubscription(id uint) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	err := r.db.First(&sub, id).Error
+	return &sub, err
+}
+
+// ListSubscriptions récupère tous les abonnements, du plus ancien au plus récent.
+func (r *GormWebhookRepository) ListSubscriptions() ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	err := r.db.Order("id").Find(&subs).Error
+	return subs, err
+}
+
+// DeleteSubscription supprime un abonnement. Ses livraisons restent dans le journal ;
+// dans la même transaction, celles encore en attente sont marquées en échec et ne sont plus retentées.
+func (r *GormWebhookRepository) DeleteSubscription(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.WebhookDelivery{}).
+			Where("subscription_id = ? AND status = ?", id, models.WebhookDeliveryPending).
+			Updates(map[string]interface{}{
+				"status":          models.WebhookDeliveryFailed,
+				"next_attempt_at": nil,
+				"last_error":      "subscription deleted",
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&models.WebhookSubscription{}, id).Error
+	})
+}
+
+// CreateDelivery insère une livraison.
+func (r *GormWebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// GetDelivery récupère une livraison par son identifiant.
+// Il renvoie gorm.ErrRecordNotFound si la livraison n'existe pas.
+func (r *GormWebhookRepository) GetDelivery(id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.First(&delivery, id).Error
+	return &delivery, err
+}
+
+// ListDeliveries liste les livraisons du filtre, de la plus récente à la plus ancienne.
+func (r *GormWebhookRepository) ListDeliveries(filter DeliveryFilter) ([]models.WebhookDelivery, error) {
+	query := r.db.Model(&models.WebhookDelivery{}).Order("id DESC")
+	if filter.SubscriptionID != 0 {
+		query = query.Where("subscription_id = ?", filter.SubscriptionID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var deliveries []models.WebhookDelivery
+	err := query.Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ListDueDeliveries liste, de la plus ancienne à la plus récente, les livraisons en attente
+// dont la prochaine tentative est due à l'instant now.
+func (r *GormWebhookRepository) ListDueDeliveries(now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.WebhookDelivery{}).
+		Where("status = ? AND next_attempt_at IS NOT NULL AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// ClaimDelivery réserve une livraison due pour une tentative en repoussant sa prochaine tentative
+// de lease : la condition est évaluée par la base de données dans la même requête UPDATE,
+// ce qui empêche deux workers (ou deux serveurs) de l'envoyer en même temps.
+// Si la tentative n'aboutit pas (arrêt du serveur), la livraison redevient due après lease.
+// Retourne false si la livraison n'est pas (ou plus) due.
+func (r *GormWebhookRepository) ClaimDelivery(id uint, now time.Time, lease time.Duration) (bool, error) {
+	result := r.db.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at IS NOT NULL AND next_attempt_at <= ?", id, models.WebhookDeliveryPending, now).
+		UpdateColumn("next_attempt_at", now.Add(lease))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateDelivery enregistre le résultat d'une tentative de livraison, si la livraison est toujours
+// en attente : une livraison passée en échec pendant la tentative (abonnement supprimé) le reste.
+func (r *GormWebhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Model(delivery).
+		Where("status = ?", models.WebhookDeliveryPending).
+		Select("status", "attempts", "last_status_code", "last_error", "next_attempt_at", "delivered_at").
+		Updates(delivery).Error
+}
+
+// WithinTransaction exécute fn dans une transaction de base de données.
+// Les dépôts passés à fn partagent la transaction ; elle est annulée si fn retourne une erreur.
+func (r *GormWebhookRepository) WithinTransaction(fn func(tx Tx) error) error {
+	return withinTransaction(r.db, fn)
+}
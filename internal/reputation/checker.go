@@ -0,0 +1,273 @@
+package reputation
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Quanghng/url-shortener/internal/config"
+)
+
+// Verdict est le résultat de la vérification de réputation d'une URL.
+type Verdict struct {
+	Flagged bool   // true si l'URL figure dans une liste de blocage
+	Reason  string // Description lisible de la correspondance
+	Source  string // Nom du fichier de liste ayant déclenché la correspondance
+}
+
+// listFile mémorise la date de modification d'un fichier déjà chargé.
+type listFile struct {
+	path    string
+	modTime time.Time
+}
+
+// Checker vérifie les URLs de destination contre des listes de blocage locales :
+//   - listes de domaines (un domaine par ligne, sous-domaines inclus) ;
+//   - listes de préfixes de hachage SHA-256 (hexadécimal, un préfixe par ligne),
+//     calculés sur les expressions "hôte/chemin" de l'URL, à la manière de Safe Browsing.
+//
+// Les fichiers sont rechargés depuis le disque lorsqu'ils sont modifiés (voir Refresh).
+type Checker struct {
+	domainFiles []listFile
+	hashFiles   []listFile
+	interval    time.Duration
+
+	mu       sync.RWMutex
+	domains  map[string]string         // domaine -> source
+	prefixes map[int]map[string]string // longueur du préfixe hexadécimal -> préfixe -> source
+}
+
+// NewChecker crée un Checker à partir de la section 'reputation' de la configuration
+// et charge immédiatement les listes.
+func NewChecker(cfg config.ReputationConfig) *Checker {
+	c := &Checker{
+		interval: time.Duration(cfg.RefreshMinutes) * time.Minute,
+		domains:  make(map[string]string),
+		prefixes: make(map[int]map[string]string),
+	}
+	for _, path := range cfg.DomainLists {
+		c.domainFiles = append(c.domainFiles, listFile{path: path})
+	}
+	for _, path := range cfg.HashPrefixLists {
+		c.hashFiles = append(c.hashFiles, listFile{path: path})
+	}
+	c.reload()
+	return c
+}
+
+// Start recharge périodiquement les listes modifiées sur le disque.
+// Cette fonction est conçue pour être lancée dans une goroutine séparée.
+func (c *Checker) Start() {
+	if c.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.Refresh()
+	}
+}
+
+// Refresh recharge les listes si au moins un fichier a été modifié depuis le dernier chargement.
+func (c *Checker) Refresh() {
+	if c.changed(c.domainFiles) || c.changed(c.hashFiles) {
+		c.reload()
+	}
+}
+
+// Check vérifie une URL contre les listes de domaines puis contre les préfixes de hachage.
+func (c *Checker) Check(rawURL string) Verdict {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Hostname() == "" {
+		return Verdict{}
+	}
+	host := canonicalHost(u.Hostname())
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// 1) Domaine exact ou domaine parent
+	for _, candidate := range hostSuffixes(host) {
+		if source, ok := c.domains[candidate]; ok {
+			return Verdict{
+				Flagged: true,
+				Reason:  fmt.Sprintf("domain %s is blocklisted", candidate),
+				Source:  source,
+			}
+		}
+	}
+
+	// 2) Préfixes de hachage des expressions hôte/chemin
+	if len(c.prefixes) == 0 {
+		return Verdict{}
+	}
+	for _, expr := range urlExpressions(host, u) {
+		sum := sha256.Sum256([]byte(expr))
+		digest := hex.EncodeToString(sum[:])
+		for length, set := range c.prefixes {
+			if source, ok := set[digest[:length]]; ok {
+				return Verdict{
+					Flagged: true,
+					Reason:  fmt.Sprintf("URL expression %q matches a blocklisted hash prefix", expr),
+					Source:  source,
+				}
+			}
+		}
+	}
+	return Verdict{}
+}
+
+// changed indique si l'un des fichiers a été modifié (ou est apparu) depuis le dernier chargement.
+func (c *Checker) changed(files []listFile) bool {
+	for _, f := range files {
+		info, err := os.Stat(f.path)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Equal(f.modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// reload relit l'ensemble des fichiers et remplace les listes en mémoire.
+func (c *Checker) reload() {
+	domains := make(map[string]string)
+	prefixes := make(map[int]map[string]string)
+
+	for i := range c.domainFiles {
+		f := &c.domainFiles[i]
+		source := filepath.Base(f.path)
+		f.modTime = readList(f.path, func(entry string) {
+			domains[canonicalHost(entry)] = source
+		})
+	}
+	for i := range c.hashFiles {
+		f := &c.hashFiles[i]
+		source := filepath.Base(f.path)
+		f.modTime = readList(f.path, func(entry string) {
+			prefix := strings.ToLower(entry)
+			if _, err := hex.DecodeString(prefix); err != nil || len(prefix) < 8 || len(prefix) > 64 {
+				log.Printf("[REPUTATION] Préfixe de hachage ignoré dans %s: %q", f.path, entry)
+				return
+			}
+			if prefixes[len(prefix)] == nil {
+				prefixes[len(prefix)] = make(map[string]string)
+			}
+			prefixes[len(prefix)][prefix] = source
+		})
+	}
+
+	c.mu.Lock()
+	c.domains = domains
+	c.prefixes = prefixes
+	c.mu.Unlock()
+
+	prefixCount := 0
+	for _, set := range prefixes {
+		prefixCount += len(set)
+	}
+	log.Printf("[REPUTATION] Listes chargées : %d domaine(s), %d préfixe(s) de hachage.", len(domains), prefixCount)
+}
+
+// readList lit un fichier ligne par ligne en ignorant les lignes vides et les commentaires (#),
+// et retourne sa date de modification.
+func readList(path string, add func(entry string)) time.Time {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Printf("[REPUTATION] Impossible de lire la liste %s: %v", path, err)
+		return time.Time{}
+	}
+	defer file.Close()
+
+	var modTime time.Time
+	if info, err := file.Stat(); err == nil {
+		modTime = info.ModTime()
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Seul le premier champ est pris en compte (permet les commentaires en fin de ligne)
+		add(strings.Fields(line)[0])
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("[REPUTATION] Erreur de lecture de la liste %s: %v", path, err)
+	}
+	return modTime
+}
+
+// canonicalHost met un nom d'hôte sous forme canonique (minuscules, sans point final).
+func canonicalHost(host string) string {
+	return strings.Trim(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+// hostSuffixes retourne l'hôte et ses domaines parents (a.b.example.com -> b.example.com -> example.com).
+// Les adresses IP ne sont pas découpées.
+func hostSuffixes(host string) []string {
+	if net.ParseIP(host) != nil {
+		return []string{host}
+	}
+	labels := strings.Split(host, ".")
+	suffixes := make([]string, 0, len(labels))
+	for i := 0; i < len(labels)-1; i++ {
+		suffixes = append(suffixes, strings.Join(labels[i:], "."))
+	}
+	if len(suffixes) == 0 {
+		suffixes = append(suffixes, host)
+	}
+	return suffixes
+}
+
+// urlExpressions construit les combinaisons hôte/chemin à hacher, comme le fait Safe Browsing :
+// jusqu'à 5 suffixes d'hôte et 6 préfixes de chemin (chemin complet avec et sans requête, puis préfixes).
+func urlExpressions(host string, u *url.URL) []string {
+	hosts := hostSuffixes(host)
+	if len(hosts) > 5 {
+		hosts = append(hosts[:1], hosts[len(hosts)-4:]...)
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	paths := []string{}
+	if u.RawQuery != "" {
+		paths = append(paths, path+"?"+u.RawQuery)
+	}
+	paths = append(paths, path)
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	prefix := "/"
+	paths = append(paths, prefix)
+	for i := 0; i < len(segments)-1 && i < 3; i++ {
+		prefix += segments[i] + "/"
+		paths = append(paths, prefix)
+	}
+
+	seen := make(map[string]bool)
+	expressions := make([]string, 0, len(hosts)*len(paths))
+	for _, h := range hosts {
+		for _, p := range paths {
+			expr := h + p
+			if !seen[expr] {
+				seen[expr] = true
+				expressions = append(expressions, expr)
+			}
+		}
+	}
+	return expressions
+}
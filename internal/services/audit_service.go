@@ -0,0 +1,140 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
+)
+
+// AuditQuery décrit une consultation du journal d'audit depuis la CLI ou l'API.
+type AuditQuery struct {
+	Actor      string // Auteur exact
+	Action     string // Action exacte ("link.deleted") ou famille ("link.")
+	TargetType string // link, report, domain ou webhook
+	Target     string // Cible exacte ([domaine/]code court, identifiant de signalement, domaine)
+	From       string // Début de la période : date (2025-06-01) ou RFC 3339, inclus
+	To         string // Fin de la période : date incluse (2025-06-30) ou RFC 3339, exclu
+	Limit      int
+	Offset     int
+}
+
+// AuditService enregistre et consulte le journal d'audit des actions d'administration
+// (création, modification, suppression, désactivation des liens, modération, purges).
+// Un *AuditService nil est valide : les actions ne sont alors pas journalisées.
+type AuditService struct {
+	auditRepo repository.AuditRepository
+}
+
+// NewAuditService crée et retourne une nouvelle instance de AuditService.
+func NewAuditService(auditRepo repository.AuditRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// Record ajoute une entrée au journal d'audit dans la transaction tx de l'action journalisée :
+// l'entrée et l'action sont validées ensemble. Une erreur retournée doit faire échouer
+// l'action (annulation de la transaction) pour qu'aucune action n'échappe au journal.
+func (s *AuditService) Record(tx repository.Tx, entry models.AuditEntry) error {
+	if s == nil {
+		return nil
+	}
+	if err := tx.Audit.CreateAuditEntry(&entry); err != nil {
+		return fmt.Errorf("failed to record audit entry %s on %s %s: %w", entry.Action, entry.TargetType, entry.Target, err)
+	}
+	return nil
+}
+
+// ListEntries retourne les entrées du journal d'audit correspondant à la requête,
+// de la plus récente à la plus ancienne (50 par défaut, 500 au plus).
+func (s *AuditService) ListEntries(query AuditQuery) ([]models.AuditEntry, error) {
+	filter := repository.AuditFilter{
+		Actor:      strings.TrimSpace(query.Actor),
+		Action:     strings.ToLower(strings.TrimSpace(query.Action)),
+		TargetType: strings.ToLower(strings.TrimSpace(query.TargetType)),
+		Target:     strings.TrimSpace(query.Target),
+		Limit:      query.Limit,
+		Offset:     query.Offset,
+	}
+	switch filter.TargetType {
+	case "", models.AuditTargetLink, models.AuditTargetReport, models.AuditTargetDomain, models.AuditTargetWebhook:
+	default:
+		return nil, fmt.Errorf("%w: target_type must be %s, %s, %s or %s", ErrInvalidAuditQuery,
+			models.AuditTargetLink, models.AuditTargetReport, models.AuditTargetDomain, models.AuditTargetWebhook)
+	}
+
+	var err error
+	if filter.From, err = parseExportDate(query.From, false); err != nil {
+		return nil, fmt.Errorf("%w: invalid from date %q", ErrInvalidAuditQuery, query.From)
+	}
+	if filter.To, err = parseExportDate(query.To, true); err != nil {
+		return nil, fmt.Errorf("%w: invalid to date %q", ErrInvalidAuditQuery, query.To)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidAuditQuery)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.auditRepo.ListAuditEntries(filter)
+}
+
+// SetAuditLog définit le journal d'audit des créations, modifications et suppressions de liens.
+func (s *LinkService) SetAuditLog(audit *AuditService) {
+	s.audit = audit
+}
+
+// linkEntry construit une entrée d'audit portant sur un lien.
+func linkEntry(link *models.Link, action, actor string, diff models.AuditDiff, note string) models.AuditEntry {
+	return models.AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		TargetType: models.AuditTargetLink,
+		Target:     link.AuditTarget(),
+		Diff:       diff,
+		Note:       note,
+	}
+}
+
+// creationDiff décrit un lien créé : sa destination et ses options renseignées.
+func creationDiff(link *models.Link) models.AuditDiff {
+	diff := models.AuditDiff{"long_url": {New: link.LongURL}}
+	if link.IsPasswordProtected() {
+		diff["password_protected"] = models.AuditChange{New: true}
+	}
+	if link.MaxClicks > 0 {
+		diff["max_clicks"] = models.AuditChange{New: link.MaxClicks}
+	}
+	if link.StartsAt != nil {
+		diff["starts_at"] = models.AuditChange{New: link.StartsAt}
+	}
+	if len(link.DeviceRules) > 0 || len(link.GeoRules) > 0 || len(link.Variants) > 0 {
+		diff["routing"] = models.AuditChange{New: true}
+	}
+	if len(link.Tags) > 0 {
+		diff["tags"] = models.AuditChange{New: tagNames(link.Tags)}
+	}
+	if link.Campaign != nil {
+		diff["campaign"] = models.AuditChange{New: link.Campaign.Name}
+	}
+	if link.Folder != "" {
+		diff["folder"] = models.AuditChange{New: link.Folder}
+	}
+	return diff
+}
+
+// tagNames retourne les noms des étiquettes.
+func tagNames(tags []models.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
+)
+
+// MaxBulkLinks est le nombre maximal de liens créés en une seule opération groupée.
+const MaxBulkLinks = 1000
+
+// BulkLinkInput est une ligne d'une création groupée.
+type BulkLinkInput struct {
+	LongURL string
+	Options CreateLinkOptions
+	Error   string // Erreur détectée à la lecture de la ligne (JSON, CSV) : la ligne est rejetée telle quelle
+}
+
+// BulkResult est le résultat de la création d'une ligne : le code court attribué ou l'erreur rencontrée.
+type BulkResult struct {
+	Row       int          `json:"row"` // Numéro de la ligne dans la requête, à partir de 1
+	LongURL   string       `json:"long_url"`
+	ShortCode string       `json:"short_code,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	Existing  bool         `json:"existing,omitempty"` // Lien existant réutilisé (déduplication)
+	Link      *models.Link `json:"-"`                  // Lien créé ou réutilisé (nil en cas d'erreur)
+}
+
+// CreateLinks crée plusieurs liens. Toutes les lignes sont validées avant toute écriture.
+//   - atomic = true : les liens sont créés dans une transaction ; si une seule ligne est invalide
+//     ou échoue, aucun lien n'est créé et ErrBulkRejected est retournée avec le détail par ligne ;
+//   - atomic = false : chaque ligne valide est créée indépendamment des autres.
+func (s *LinkService) CreateLinks(inputs []BulkLinkInput, atomic bool) ([]BulkResult, error) {
+	if len(inputs) == 0 || len(inputs) > MaxBulkLinks {
+		return nil, fmt.Errorf("%w: between 1 and %d links are required", ErrInvalidBulkRequest, MaxBulkLinks)
+	}
+
+	// 1) Validation de toutes les lignes
+	results := make([]BulkResult, len(inputs))
+	prepared := make([]*models.Link, len(inputs))
+	options := make([]CreateLinkOptions, len(inputs))
+	invalid := 0
+	for i, input := range inputs {
+		results[i] = BulkResult{Row: i + 1, LongURL: input.LongURL}
+		if input.Error != "" {
+			results[i].Error = input.Error
+			invalid++
+			continue
+		}
+		link, opts, err := s.prepareLink(input.LongURL, input.Options)
+		if err != nil {
+			results[i].Error = err.Error()
+			invalid++
+			continue
+		}
+		prepared[i], options[i] = link, opts
+	}
+
+	// 2) Mode atomique : tout ou rien
+	if atomic {
+		if invalid > 0 {
+			return results, fmt.Errorf("%w: %d invalid row(s)", ErrBulkRejected, invalid)
+		}
+		err := s.linkRepo.WithinTransaction(func(tx repository.Tx) error {
+			for i, link := range prepared {
+				if err := s.saveOrReuseLink(tx, &results[i], link, options[i]); err != nil {
+					results[i].Error = err.Error()
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			// Transaction annulée : les liens attribués aux lignes précédentes n'existent pas
+			for i := range results {
+				results[i] = BulkResult{Row: results[i].Row, LongURL: results[i].LongURL, Error: results[i].Error}
+			}
+			return results, fmt.Errorf("%w: %v", ErrBulkRejected, err)
+		}
+		for i := range results {
+			if !results[i].Existing {
+				s.enqueueMetadata(results[i].Link)
+				s.publishLinkCreated(results[i].Link)
+			}
+		}
+		return results, nil
+	}
+
+	// 3) Mode ligne par ligne : une transaction par ligne (lien et entrée d'audit)
+	for i, link := range prepared {
+		if link == nil {
+			continue
+		}
+		err := s.linkRepo.WithinTransaction(func(tx repository.Tx) error {
+			return s.saveOrReuseLink(tx, &results[i], link, options[i])
+		})
+		if err != nil {
+			results[i] = BulkResult{Row: results[i].Row, LongURL: results[i].LongURL, Error: err.Error()}
+			continue
+		}
+		if !results[i].Existing {
+			s.enqueueMetadata(link)
+			s.publishLinkCreated(link)
+		}
+	}
+	return results, nil
+}
+
+// saveOrReuseLink enregistre le lien d'une ligne, ou réutilise un lien existant en mode déduplication
+// (y compris un lien créé par une ligne précédente du même lot), et complète son résultat.
+// Un lien créé est journalisé dans la même transaction tx.
+func (s *LinkService) saveOrReuseLink(tx repository.Tx, result *BulkResult, link *models.Link, opts CreateLinkOptions) error {
+	if s.shouldDedupe(opts) {
+		existing, err := findDuplicate(tx.Links, link)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			result.ShortCode, result.Link, result.Existing = existing.ShortCode, existing, true
+			return nil
+		}
+	}
+	if err := s.saveLink(tx.Links, tx.Tags, link, opts); err != nil {
+		return err
+	}
+	if err := s.audit.Record(tx, linkEntry(link, models.AuditLinkCreated, opts.Actor, creationDiff(link), "")); err != nil {
+		return err
+	}
+	result.ShortCode, result.Link = link.ShortCode, link
+	return nil
+}
+
+// CountBulkFailures retourne le nombre de lignes en erreur.
+func CountBulkFailures(results []BulkResult) int {
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	return failed
+}
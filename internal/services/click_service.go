@@ -1,6 +1,9 @@
 package services
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/Quanghng/url-shortener/internal/models"
 	"github.com/Quanghng/url-shortener/internal/repository" // Importe le package repository
 )
@@ -31,3 +34,94 @@ func (s *ClickService) GetClicksCountByLinkID(linkID uint) (int, error) {
 	// Appelle le ClickRepository pour compter les clics par LinkID
 	return s.clickRepo.CountClicksByLinkID(linkID)
 }
+
+// GetClicksBreakdown retourne la répartition des clics d'un lien selon une dimension
+// (ex: "country" pour le pays du visiteur).
+func (s *ClickService) GetClicksBreakdown(linkID uint, dimension string) (map[string]int, error) {
+	return s.clickRepo.CountClicksGroupedBy(linkID, dimension)
+}
+
+// VariantStats contient le nombre de clics d'une variante du test A/B.
+type VariantStats struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+	Clicks int    `json:"clicks"`
+}
+
+// GetVariantStats retourne les clics de chaque variante du test A/B d'un lien, dans l'ordre
+// de déclaration (variantes sans clic comprises) ; vide si le lien n'a pas de variante.
+func (s *ClickService) GetVariantStats(link *models.Link) ([]VariantStats, error) {
+	if len(link.Variants) == 0 {
+		return nil, nil
+	}
+	byVariant, err := s.clickRepo.CountClicksByVariant(link.ID)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]VariantStats, 0, len(link.Variants))
+	for _, variant := range link.Variants {
+		stats = append(stats, VariantStats{
+			Name:   variant.Name,
+			URL:    variant.URL,
+			Weight: variant.Weight,
+			Clicks: byVariant[variant.Name],
+		})
+	}
+	return stats, nil
+}
+
+// GroupStatsQuery décrit les statistiques agrégées demandées pour un ensemble de liens.
+type GroupStatsQuery struct {
+	Filter   repository.LinkFilter // Étiquette, campagne ou dossier
+	Interval string                // Granularité de la série temporelle : hour, day (défaut), week, month
+	Days     int                   // Profondeur de la série temporelle en jours (défaut 30)
+	Top      int                   // Nombre de liens du classement (défaut 10)
+}
+
+// GroupStats regroupe les statistiques agrégées d'une étiquette, d'une campagne ou d'un dossier.
+type GroupStats struct {
+	TotalClicks int                          `json:"total_clicks"` // Clics depuis la création des liens
+	Timeseries  []repository.TimeseriesPoint `json:"timeseries"`   // Clics par période sur la fenêtre demandée
+	TopLinks    []repository.LinkClicks      `json:"top_links"`    // Liens les plus cliqués depuis leur création
+}
+
+// GetGroupStats calcule le total des clics, la série temporelle et les liens les plus cliqués
+// d'un ensemble de liens.
+func (s *ClickService) GetGroupStats(query GroupStatsQuery) (*GroupStats, error) {
+	query.Filter = NormalizeLinkFilter(query.Filter)
+	if query.Filter.IsEmpty() {
+		return nil, fmt.Errorf("%w: a tag, campaign or folder is required", ErrInvalidStatsQuery)
+	}
+	if query.Interval == "" {
+		query.Interval = "day"
+	}
+	if !repository.IsValidTimeseriesInterval(query.Interval) {
+		return nil, fmt.Errorf("%w: interval must be hour, day, week or month", ErrInvalidStatsQuery)
+	}
+	if query.Days <= 0 {
+		query.Days = 30
+	}
+	if query.Top <= 0 {
+		query.Top = 10
+	}
+	if query.Days > 366 || query.Top > 100 {
+		return nil, fmt.Errorf("%w: days is limited to 366 and top to 100", ErrInvalidStatsQuery)
+	}
+
+	total, err := s.clickRepo.CountClicksForLinks(query.Filter, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	since := time.Now().AddDate(0, 0, -query.Days)
+	timeseries, err := s.clickRepo.ClickTimeseries(query.Filter, since, query.Interval)
+	if err != nil {
+		return nil, err
+	}
+	top, err := s.clickRepo.TopLinksByClicks(query.Filter, time.Time{}, query.Top)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GroupStats{TotalClicks: total, Timeseries: timeseries, TopLinks: top}, nil
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
+)
+
+// SetDedupe définit le mode de déduplication par défaut (surchargé par CreateLinkOptions.Dedupe).
+// En mode déduplication, créer un lien vers une destination déjà raccourcie retourne le lien existant.
+func (s *LinkService) SetDedupe(enabled bool) {
+	s.dedupe = enabled
+}
+
+// shouldDedupe indique si la création doit réutiliser un lien existant : la déduplication doit être
+// demandée (par la requête ou par défaut) et le lien ne doit porter aucune option, sans quoi deux liens
+// vers la même URL ne sont pas équivalents (mot de passe, routage, étiquettes...).
+func (s *LinkService) shouldDedupe(opts CreateLinkOptions) bool {
+	enabled := s.dedupe
+	if opts.Dedupe != nil {
+		enabled = *opts.Dedupe
+	}
+	return enabled && opts.Password == "" && opts.MaxClicks == 0 &&
+		opts.StartsAt == nil && opts.Schedule == nil && opts.FallbackURL == "" &&
+		len(opts.DeviceRules) == 0 && len(opts.GeoRules) == 0 && len(opts.Variants) == 0 &&
+		len(opts.UTM.Values()) == 0 && !opts.ForwardQuery &&
+		len(opts.Tags) == 0 && opts.Campaign == "" && opts.Folder == "" &&
+		opts.SocialCard.IsEmpty()
+}
+
+// findDuplicate cherche, sur le domaine du lien, un lien existant vers la même URL canonique
+// qui redirige sans condition. Retourne nil si aucun lien ne convient.
+func findDuplicate(linkRepo repository.LinkRepository, link *models.Link) (*models.Link, error) {
+	candidates, err := linkRepo.FindLinksByURLHash(link.Domain, link.URLHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up duplicate links: %w", err)
+	}
+	for i := range candidates {
+		if isPlainLink(&candidates[i]) {
+			return &candidates[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// isPlainLink indique si un lien redirige toujours vers sa URL longue, sans option ni restriction.
+func isPlainLink(link *models.Link) bool {
+	return !link.IsPasswordProtected() && link.MaxClicks == 0 && link.StartsAt == nil && link.Schedule == nil &&
+		len(link.DeviceRules) == 0 && len(link.GeoRules) == 0 && len(link.Variants) == 0 &&
+		len(link.UTM.Values()) == 0 && !link.ForwardQuery &&
+		!link.IsDisabled && link.FlaggedAt == nil && link.SocialCard.IsEmpty() &&
+		link.CampaignID == nil && link.Folder == "" && len(link.Tags) == 0
+}
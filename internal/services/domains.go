@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+)
+
+// SetBaseURL définit l'URL de base (server.base_url) des URLs courtes complètes.
+// Son hôte est le domaine par défaut : les liens qui y sont rattachés ont un Domain vide.
+func (s *LinkService) SetBaseURL(baseURL string) {
+	s.baseURL = strings.TrimRight(baseURL, "/")
+	if u, err := url.Parse(s.baseURL); err == nil {
+		s.defaultHost = hostname(u.Host)
+	}
+}
+
+// SetDomains définit les domaines personnalisés (server.domains) à partir de leurs URLs de base
+// (ex: "https://go.acme.io"). Un même code court peut exister sur chacun d'eux.
+func (s *LinkService) SetDomains(baseURLs []string) error {
+	domains := make(map[string]string, len(baseURLs))
+	for _, raw := range baseURLs {
+		u, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid domain %q: expected a base URL such as https://go.acme.io", raw)
+		}
+		domains[hostname(u.Host)] = strings.TrimRight(u.String(), "/")
+	}
+	s.domains = domains
+	return nil
+}
+
+// DomainForHost retourne le domaine des liens servis pour l'en-tête Host d'une requête :
+// le domaine personnalisé correspondant, sinon le domaine par défaut ("").
+func (s *LinkService) DomainForHost(host string) string {
+	host = hostname(host)
+	if _, ok := s.domains[host]; ok && host != s.defaultHost {
+		return host
+	}
+	return ""
+}
+
+// normalizeDomain retourne le domaine tel qu'enregistré sur un lien : "" pour le domaine
+// par défaut (valeur vide ou hôte de server.base_url), sinon l'hôte d'un domaine configuré.
+func (s *LinkService) normalizeDomain(domain string) (string, error) {
+	host := hostname(strings.TrimSpace(domain))
+	if host == "" || host == s.defaultHost {
+		return "", nil
+	}
+	if _, ok := s.domains[host]; !ok {
+		return "", fmt.Errorf("%w: %q is not a configured domain", ErrInvalidDomain, domain)
+	}
+	return host, nil
+}
+
+// ShortURL retourne l'URL courte complète d'un lien sur son domaine (ex: "https://go.acme.io/xyz123").
+func (s *LinkService) ShortURL(link *models.Link) string {
+	if link.Domain == "" {
+		return s.baseURL + "/" + link.ShortCode
+	}
+	if base, ok := s.domains[link.Domain]; ok {
+		return base + "/" + link.ShortCode
+	}
+	// Domaine retiré de la configuration : l'URL reste celle sous laquelle le lien a été diffusé
+	return "https://" + link.Domain + "/" + link.ShortCode
+}
+
+// hostname extrait le nom d'hôte, en minuscules et sans port, d'un en-tête Host ou d'un domaine.
+func hostname(host string) string {
+	return strings.ToLower((&url.URL{Host: host}).Hostname())
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
+)
+
+// EnableLink réactive un lien désactivé par la modération ou signalé par la vérification de réputation :
+// la désactivation et le signalement sont levés et le lien redirige de nouveau. Une destination
+// toujours présente dans une liste de blocage est signalée de nouveau à la prochaine vérification du moniteur.
+// Retourne ErrLinkNotDisabled si le lien n'est ni désactivé ni signalé.
+func (s *LinkService) EnableLink(domain, shortCode, actor, note string) (*models.Link, error) {
+	link, err := s.GetLinkByShortCode(domain, shortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := link.ClearFlag()
+	if link.IsDisabled {
+		diff["is_disabled"] = models.AuditChange{Old: true, New: false}
+		diff["disabled_reason"] = models.AuditChange{Old: link.DisabledReason, New: ""}
+		link.IsDisabled = false
+		link.DisabledReason = ""
+	}
+	if len(diff) == 0 {
+		return nil, ErrLinkNotDisabled
+	}
+
+	err = s.linkRepo.WithinTransaction(func(tx repository.Tx) error {
+		if err := tx.Links.UpdateFlag(link); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrLinkNotFound // Mis à la corbeille ou destination modifiée entre-temps
+			}
+			return fmt.Errorf("failed to enable link: %w", err)
+		}
+		return s.audit.Record(tx, linkEntry(link, models.AuditLinkEnabled, actor, diff, note))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
@@ -1,8 +1,43 @@
 package services
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrShortCodeRequired = errors.New("short code is required")
-	ErrLinkNotFound      = errors.New("short link not found")
+	ErrShortCodeRequired    = errors.New("short code is required")
+	ErrLinkNotFound         = errors.New("short link not found")
+	ErrDestinationFlagged   = errors.New("destination is flagged as malicious")
+	ErrInvalidPassword      = errors.New("invalid password")
+	ErrLinkExhausted        = errors.New("short link has reached its maximum number of clicks")
+	ErrInvalidMaxClicks     = errors.New("max clicks must be zero (unlimited) or positive")
+	ErrInvalidSchedule      = errors.New("invalid schedule")
+	ErrInvalidRoutingRule   = errors.New("invalid routing rule")
+	ErrInvalidQueryOption   = errors.New("invalid query string option")
+	ErrInvalidGrouping      = errors.New("invalid tag, campaign or folder")
+	ErrInvalidStatsQuery    = errors.New("invalid statistics query")
+	ErrInvalidBulkRequest   = errors.New("invalid bulk request")
+	ErrBulkRejected         = errors.New("bulk creation rejected, no link was created")
+	ErrInvalidExportQuery   = errors.New("invalid export query")
+	ErrInvalidDomain        = errors.New("invalid domain")
+	ErrInvalidSocialCard    = errors.New("invalid social card")
+	ErrRevisionNotFound     = errors.New("revision not found")
+	ErrDestinationUnchanged = errors.New("destination is unchanged")
+	ErrNotInTrash           = errors.New("short link is not in the trash")
+	ErrLinkNotDisabled      = errors.New("short link is neither disabled nor flagged")
+	ErrInvalidAuditQuery    = errors.New("invalid audit query")
+	ErrInvalidWebhook       = errors.New("invalid webhook subscription")
+	ErrWebhookNotFound      = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrInvalidDeliveryQuery = errors.New("invalid delivery query")
+	ErrWebhooksDisabled     = errors.New("webhooks are disabled")
+
+	ErrReportNotFound          = errors.New("report not found")
+	ErrInvalidReportReason     = errors.New("invalid report reason")
+	ErrInvalidModerationAction = errors.New("invalid moderation action")
 )
+
+// ErrLinkDeleted est retournée pour un lien à la corbeille. Elle satisfait aussi
+// errors.Is(err, ErrLinkNotFound) : hors de la redirection (HTTP 410), le lien est introuvable.
+var ErrLinkDeleted = fmt.Errorf("%w: link is in the trash", ErrLinkNotFound)
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Quanghng/url-shortener/internal/export"
+	"github.com/Quanghng/url-shortener/internal/repository"
+)
+
+// Jeux de données exportables
+const (
+	ExportLinks  = "links"
+	ExportClicks = "clicks"
+)
+
+// exportDateLayout est le format des dates de période acceptées en plus de RFC 3339.
+const exportDateLayout = "2006-01-02"
+
+// ExportQuery décrit un export demandé depuis la CLI ou l'API.
+type ExportQuery struct {
+	Dataset   string // links ou clicks
+	Format    string // csv (défaut), jsonl ou parquet
+	ShortCode string // Restreint l'export à un lien (vide = tous)
+	Domain    string // Domaine personnalisé du lien filtré (vide = domaine par défaut)
+	From      string // Début de la période : date (2025-06-01) ou RFC 3339, inclus
+	To        string // Fin de la période : date incluse (2025-06-30) ou RFC 3339, exclu
+}
+
+// Export est un export validé, prêt à être écrit.
+type Export struct {
+	Dataset string
+	Format  string
+	filter  repository.ExportFilter
+	repo    repository.ExportRepository
+}
+
+// ExportService fournit l'export des liens et des clics vers un entrepôt de données.
+type ExportService struct {
+	exportRepo repository.ExportRepository // Parcours des lignes à exporter
+	links      *LinkService                // Résolution du code court filtré (domaines configurés)
+}
+
+// NewExportService crée et retourne une nouvelle instance de ExportService.
+// links résout le lien filtré selon les domaines configurés (voir SetBaseURL et SetDomains).
+func NewExportService(exportRepo repository.ExportRepository, links *LinkService) *ExportService {
+	return &ExportService{
+		exportRepo: exportRepo,
+		links:      links,
+	}
+}
+
+// PrepareExport valide la requête et résout le lien filtré, avant toute écriture :
+// l'appelant peut ainsi encore signaler une erreur (ex: statut HTTP 400).
+func (s *ExportService) PrepareExport(query ExportQuery) (*Export, error) {
+	dataset := strings.ToLower(strings.TrimSpace(query.Dataset))
+	if dataset != ExportLinks && dataset != ExportClicks {
+		return nil, fmt.Errorf("%w: dataset must be %s or %s", ErrInvalidExportQuery, ExportLinks, ExportClicks)
+	}
+	format := strings.ToLower(strings.TrimSpace(query.Format))
+	if format == "" {
+		format = export.FormatCSV
+	}
+	if !export.IsValidFormat(format) {
+		return nil, fmt.Errorf("%w: format must be csv, jsonl or parquet", ErrInvalidExportQuery)
+	}
+
+	var filter repository.ExportFilter
+	var err error
+	if filter.From, err = parseExportDate(query.From, false); err != nil {
+		return nil, fmt.Errorf("%w: invalid from date %q", ErrInvalidExportQuery, query.From)
+	}
+	if filter.To, err = parseExportDate(query.To, true); err != nil {
+		return nil, fmt.Errorf("%w: invalid to date %q", ErrInvalidExportQuery, query.To)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidExportQuery)
+	}
+
+	if code := strings.TrimSpace(query.ShortCode); code != "" {
+		domain, err := s.normalizeDomain(query.Domain)
+		if err != nil {
+			return nil, err
+		}
+		link, err := s.links.linkRepo.GetLinkByShortCode(domain, code)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrLinkNotFound
+			}
+			return nil, err
+		}
+		filter.LinkID = link.ID
+	}
+
+	return &Export{Dataset: dataset, Format: format, filter: filter, repo: s.exportRepo}, nil
+}
+
+// normalizeDomain retourne le domaine du lien filtré tel qu'enregistré sur les liens
+// (voir LinkService.normalizeDomain) ; un domaine non configuré est une requête invalide.
+func (s *ExportService) normalizeDomain(domain string) (string, error) {
+	domain, err := s.links.normalizeDomain(domain)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidExportQuery, err)
+	}
+	return domain, nil
+}
+
+// parseExportDate lit une date de période. Une date seule désigne le début du jour,
+// ou le début du jour suivant pour une borne de fin (le jour est alors inclus).
+func parseExportDate(value string, end bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation(exportDateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day, nil
+}
+
+// Filename propose un nom de fichier pour l'export (ex: "clicks.parquet").
+func (e *Export) Filename() string {
+	return e.Dataset + "." + e.Format
+}
+
+// ContentType retourne le type MIME du format de l'export.
+func (e *Export) ContentType() string {
+	return export.ContentType(e.Format)
+}
+
+// Stream écrit l'export dans out au fil de la lecture des lignes et retourne le nombre de lignes écrites.
+func (e *Export) Stream(out io.Writer) (int, error) {
+	if e.Dataset == ExportLinks {
+		return streamRows(out, e.Format, linkExportHeader, linkExportRecord, func(fn func(*repository.LinkExportRow) error) error {
+			return e.repo.EachLink(e.filter, fn)
+		})
+	}
+	return streamRows(out, e.Format, clickExportHeader, clickExportRecord, func(fn func(*repository.ClickExportRow) error) error {
+		return e.repo.EachClick(e.filter, fn)
+	})
+}
+
+// streamRows relie le parcours des lignes (each) au Writer du format demandé.
+func streamRows[T any](out io.Writer, format string, header []string, record func(*T) []string, each func(fn func(*T) error) error) (int, error) {
+	writer, err := export.NewWriter(out, format, header, record)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = each(func(row *T) error {
+		count++
+		return writer.Write(row)
+	})
+	// Le Writer est fermé même après une erreur de lecture ou d'écriture (tampons, encodeur Parquet) ;
+	// l'erreur du parcours prime alors sur celle de la fermeture.
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	return count, err
+}
+
+// Colonnes CSV des exports (mêmes noms que les champs JSON et Parquet)
+var (
+	linkExportHeader = []string{
+		"id", "domain", "short_code", "long_url", "created_at", "is_active", "is_disabled",
+		"max_clicks", "click_count", "campaign", "folder", "tags",
+	}
+	clickExportHeader = []string{
+		"id", "link_id", "domain", "short_code", "timestamp", "country", "platform",
+		"device", "variant", "source", "user_agent", "ip_address",
+	}
+)
+
+func linkExportRecord(row *repository.LinkExportRow) []string {
+	return []string{
+		strconv.FormatUint(uint64(row.ID), 10), row.Domain, row.ShortCode, row.LongURL, row.CreatedAt.Format(time.RFC3339),
+		strconv.FormatBool(row.IsActive), strconv.FormatBool(row.IsDisabled),
+		strconv.Itoa(row.MaxClicks), strconv.Itoa(row.ClickCount), row.Campaign, row.Folder, row.Tags,
+	}
+}
+
+func clickExportRecord(row *repository.ClickExportRow) []string {
+	return []string{
+		strconv.FormatUint(uint64(row.ID), 10), strconv.FormatUint(uint64(row.LinkID), 10), row.Domain, row.ShortCode,
+		row.Timestamp.Format(time.RFC3339), row.Country, row.Platform,
+		row.Device, row.Variant, row.Source, row.UserAgent, row.IPAddress,
+	}
+}
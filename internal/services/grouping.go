@@ -0,0 +1,140 @@
+package services
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
+)
+
+// Limites de l'organisation des liens.
+const (
+	maxTagsPerLink  = 20
+	maxTagLength    = 64
+	maxCampaignName = 100
+	maxFolderLength = 255
+
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+// SetTagRepository définit le dépôt des étiquettes et campagnes utilisé à la création des liens.
+func (s *LinkService) SetTagRepository(tagRepo repository.TagRepository) {
+	s.tagRepo = tagRepo
+}
+
+// ListLinks liste les liens d'une étiquette, d'une campagne ou d'un dossier (tous les liens si le filtre est vide).
+// La taille de page est de 50 liens par défaut et de 500 au maximum.
+func (s *LinkService) ListLinks(filter repository.LinkFilter) ([]models.Link, error) {
+	filter = NormalizeLinkFilter(filter)
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.linkRepo.ListLinks(filter)
+}
+
+// ListTags liste les étiquettes existantes.
+func (s *LinkService) ListTags() ([]models.Tag, error) {
+	if s.tagRepo == nil {
+		return nil, nil
+	}
+	return s.tagRepo.ListTags()
+}
+
+// ListCampaigns liste les campagnes existantes.
+func (s *LinkService) ListCampaigns() ([]models.Campaign, error) {
+	if s.tagRepo == nil {
+		return nil, nil
+	}
+	return s.tagRepo.ListCampaigns()
+}
+
+// NormalizeLinkFilter met les critères d'un filtre sous la forme utilisée en base.
+func NormalizeLinkFilter(filter repository.LinkFilter) repository.LinkFilter {
+	filter.Tag = normalizeTag(filter.Tag)
+	filter.Campaign = strings.TrimSpace(filter.Campaign)
+	filter.Folder = normalizeFolder(filter.Folder)
+	return filter
+}
+
+// validateGrouping normalise et vérifie les étiquettes, la campagne et le dossier d'un lien.
+func validateGrouping(opts *CreateLinkOptions) error {
+	seen := make(map[string]bool, len(opts.Tags))
+	tags := make([]string, 0, len(opts.Tags))
+	for _, tag := range opts.Tags {
+		tag = normalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return fmt.Errorf("%w: tag %q exceeds %d characters", ErrInvalidGrouping, tag, maxTagLength)
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	if len(tags) > maxTagsPerLink {
+		return fmt.Errorf("%w: at most %d tags per link", ErrInvalidGrouping, maxTagsPerLink)
+	}
+	opts.Tags = tags
+
+	opts.Campaign = strings.TrimSpace(opts.Campaign)
+	if len(opts.Campaign) > maxCampaignName {
+		return fmt.Errorf("%w: campaign name exceeds %d characters", ErrInvalidGrouping, maxCampaignName)
+	}
+
+	opts.Folder = normalizeFolder(opts.Folder)
+	if len(opts.Folder) > maxFolderLength {
+		return fmt.Errorf("%w: folder exceeds %d characters", ErrInvalidGrouping, maxFolderLength)
+	}
+	return nil
+}
+
+// attachGrouping associe au lien ses étiquettes et sa campagne, en les créant si elles n'existent pas.
+func attachGrouping(tagRepo repository.TagRepository, link *models.Link, opts CreateLinkOptions) error {
+	link.Folder = opts.Folder
+	if len(opts.Tags) == 0 && opts.Campaign == "" {
+		return nil
+	}
+	if tagRepo == nil {
+		return fmt.Errorf("%w: tags and campaigns are not available", ErrInvalidGrouping)
+	}
+
+	if len(opts.Tags) > 0 {
+		tags, err := tagRepo.FindOrCreateTags(opts.Tags)
+		if err != nil {
+			return fmt.Errorf("failed to save tags: %w", err)
+		}
+		link.Tags = tags
+	}
+	if opts.Campaign != "" {
+		campaign, err := tagRepo.FindOrCreateCampaign(opts.Campaign)
+		if err != nil {
+			return fmt.Errorf("failed to save campaign: %w", err)
+		}
+		link.CampaignID = &campaign.ID
+		link.Campaign = campaign
+	}
+	return nil
+}
+
+// normalizeTag met une étiquette en minuscules, sans espaces superflus.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// normalizeFolder nettoie un chemin de dossier ("/Marketing//2025/" -> "Marketing/2025").
+func normalizeFolder(folder string) string {
+	folder = strings.Trim(strings.TrimSpace(folder), "/")
+	if folder == "" {
+		return ""
+	}
+	return strings.Trim(path.Clean(folder), "/")
+}
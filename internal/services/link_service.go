@@ -1,120 +1,458 @@
 package services
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
 	"log"
-	"math/big"
 	"strings"
 	"time"
 
-	"gorm.io/gorm" // Nécessaire pour la gestion spécifique de gorm.ErrRecordNotFound
+	"golang.org/x/crypto/bcrypt" // Hachage des mots de passe des liens protégés
+	"gorm.io/gorm"               // Nécessaire pour la gestion spécifique de gorm.ErrRecordNotFound
 
+	"github.com/Quanghng/url-shortener/internal/config"
+	"github.com/Quanghng/url-shortener/internal/geoip"
 	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/policy"
 	"github.com/Quanghng/url-shortener/internal/repository" // Importe le package repository
+	"github.com/Quanghng/url-shortener/internal/reputation"
+	"github.com/Quanghng/url-shortener/internal/shortcode"
+	"github.com/Quanghng/url-shortener/internal/urlnorm"
+	"github.com/Quanghng/url-shortener/internal/useragent"
+	"github.com/Quanghng/url-shortener/internal/webhooks"
 )
 
-// Définition du jeu de caractères pour la génération des codes courts.
-const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-
 // LinkService est une structure qui fournit des méthodes pour la logique métier des liens.
 // Elle détient linkRepo qui est une référence vers une interface LinkRepository.
 type LinkService struct {
 	linkRepo repository.LinkRepository // Interface pour accéder aux données des liens
+	policy   *policy.DestinationPolicy // Politique de validation des destinations (optionnelle)
+	checker  *reputation.Checker       // Vérification des listes de blocage (optionnelle)
+	geo      *geoip.Resolver           // Résolution du pays des visiteurs (optionnelle)
+	tagRepo  repository.TagRepository  // Étiquettes et campagnes (voir SetTagRepository)
+	baseURL  string                    // URL de base des URLs courtes complètes (voir SetBaseURL)
+
+	defaultHost string            // Hôte de baseURL : domaine par défaut des liens
+	domains     map[string]string // Domaines personnalisés : hôte -> URL de base (voir SetDomains)
+
+	metadataQueue chan<- models.MetadataJob // Demandes de récupération des métadonnées (voir SetMetadataQueue)
+	codes         shortcode.Generator       // Stratégie de génération des codes courts (voir SetShortCodeGenerator)
+	dedupe        bool                      // Déduplication par défaut des destinations identiques (voir SetDedupe)
+
+	trashRetention time.Duration // Conservation des liens à la corbeille avant purge (voir SetTrashRetention)
+	audit          *AuditService // Journal d'audit des actions sur les liens (voir SetAuditLog, nil = non journalisées)
+
+	webhooks *webhooks.Dispatcher // Publication des créations aux webhooks abonnés (voir SetWebhooks, nil = non publiées)
+}
+
+// CreateLinkOptions regroupe les paramètres facultatifs de création d'un lien.
+type CreateLinkOptions struct {
+	Password  string // Mot de passe exigé avant la redirection (stocké sous forme de hachage bcrypt)
+	MaxClicks int    // Nombre maximal de redirections (0 = illimité, 1 = usage unique)
+
+	StartsAt    *time.Time       // Date d'activation du lien (nil = immédiate)
+	Schedule    *models.Schedule // Plages horaires d'ouverture (nil = toujours ouvert)
+	FallbackURL string           // URL de repli en dehors de la période d'activation
+
+	DeviceRules []models.DeviceRule // Destinations alternatives par plateforme ou appareil
+	GeoRules    []models.GeoRule    // Destinations alternatives par pays
+	Variants    []models.Variant    // Destinations du test A/B et leurs poids
+
+	UTM           *models.UTM // Paramètres utm_* ajoutés à la destination
+	ForwardQuery  bool        // Transmet les paramètres de requête du visiteur à la destination
+	QueryConflict string      // Règle de conflit des paramètres transmis (keep, override, append)
+
+	Domain string // Domaine personnalisé du lien (vide = domaine par défaut)
+
+	SocialCard models.SocialCard // Balises Open Graph servies aux robots d'aperçu (vides = métadonnées de la destination)
+
+	Tags     []string // Étiquettes (créées si elles n'existent pas)
+	Campaign string   // Campagne (créée si elle n'existe pas)
+	Folder   string   // Dossier ("marketing/2025")
+
+	Dedupe *bool // Réutilise un lien existant vers la même URL canonique (nil = réglage par défaut du service)
+
+	Actor string // Auteur de la création (journal d'audit)
+}
+
+// LinkStats regroupe les statistiques d'un lien.
+type LinkStats struct {
+	Link        *models.Link
+	TotalClicks int
 }
 
 // NewLinkService crée et retourne une nouvelle instance de LinkService.
 func NewLinkService(linkRepo repository.LinkRepository) *LinkService {
+	codes, _ := shortcode.New(config.ShortCodeConfig{}) // Configuration par défaut, toujours valide
 	return &LinkService{
 		linkRepo: linkRepo,
+		codes:    codes,
 	}
 }
 
+// SetShortCodeGenerator définit la stratégie de génération des codes courts.
+// Sans générateur, les codes sont aléatoires (base62, 6 caractères, allongés en cas de collisions répétées).
+func (s *LinkService) SetShortCodeGenerator(gen shortcode.Generator) {
+	s.codes = gen
+}
+
+// SetDestinationPolicy définit la politique appliquée aux URLs longues lors de la création des liens.
+// Sans politique, toutes les URLs sont acceptées.
+func (s *LinkService) SetDestinationPolicy(p *policy.DestinationPolicy) {
+	s.policy = p
+}
+
+// SetReputationChecker définit le vérificateur de réputation appliqué aux URLs longues à la création.
+func (s *LinkService) SetReputationChecker(checker *reputation.Checker) {
+	s.checker = checker
+}
+
+// SetGeoIPResolver définit la base GeoIP utilisée pour résoudre le pays des visiteurs.
+func (s *LinkService) SetGeoIPResolver(resolver *geoip.Resolver) {
+	s.geo = resolver
+}
+
 // GenerateShortCode génère un code court aléatoire d'une longueur spécifiée.
 // Utilise crypto/rand pour une génération cryptographiquement sécurisée.
 func (s *LinkService) GenerateShortCode(length int) (string, error) {
-	result := make([]byte, length) // Crée un slice pour stocker le code
-	charsetLen := big.NewInt(int64(len(charset)))
+	return shortcode.RandomString(shortcode.Base62, length)
+}
+
+// CreateLink crée un nouveau lien raccourci.
+// Il vérifie la destination, génère un code court unique, puis persiste le lien dans la base de données.
+// En mode déduplication, un lien existant vers la même URL canonique est retourné à la place :
+// created vaut alors false.
+func (s *LinkService) CreateLink(longURL string, opts CreateLinkOptions) (link *models.Link, created bool, err error) {
+	link, opts, err = s.prepareLink(longURL, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	// La recherche d'un doublon, le lien et son entrée d'audit forment une seule transaction :
+	// deux créations simultanées vers la même URL ne peuvent pas toutes deux conclure à l'absence
+	// de doublon et insérer chacune un lien (SQLite n'accepte qu'une transaction d'écriture à la fois).
+	var existing *models.Link
+	err = s.linkRepo.WithinTransaction(func(tx repository.Tx) error {
+		if s.shouldDedupe(opts) {
+			var err error
+			if existing, err = findDuplicate(tx.Links, link); err != nil || existing != nil {
+				return err
+			}
+		}
+		if err := s.saveLink(tx.Links, tx.Tags, link, opts); err != nil {
+			return err
+		}
+		return s.audit.Record(tx, linkEntry(link, models.AuditLinkCreated, opts.Actor, creationDiff(link), ""))
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, false, nil
+	}
+	s.enqueueMetadata(link)
+	s.publishLinkCreated(link)
+	return link, true, nil
+}
 
-	for i := 0; i < length; i++ {
-		// Génère un nombre aléatoire entre 0 et len(charset)-1
-		randomIndex, err := rand.Int(rand.Reader, charsetLen)
+// prepareLink vérifie les paramètres d'un lien et construit le modèle à enregistrer (sans code court).
+// Les options sont retournées normalisées.
+func (s *LinkService) prepareLink(longURL string, opts CreateLinkOptions) (*models.Link, CreateLinkOptions, error) {
+	if opts.MaxClicks < 0 {
+		return nil, opts, ErrInvalidMaxClicks
+	}
+	if opts.Schedule != nil {
+		if err := opts.Schedule.Validate(); err != nil {
+			return nil, opts, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+	}
+
+	// Vérifie la destination principale et l'URL de repli éventuelle
+	if err := s.validateDestination(longURL); err != nil {
+		return nil, opts, err
+	}
+	if opts.FallbackURL != "" {
+		if err := s.validateDestination(opts.FallbackURL); err != nil {
+			return nil, opts, fmt.Errorf("fallback URL: %w", err)
+		}
+	}
+	if err := s.validateDeviceRules(opts.DeviceRules); err != nil {
+		return nil, opts, err
+	}
+	if err := s.validateGeoRules(opts.GeoRules); err != nil {
+		return nil, opts, err
+	}
+	if err := s.validateVariants(opts.Variants); err != nil {
+		return nil, opts, err
+	}
+	if err := validateQueryOptions(&opts); err != nil {
+		return nil, opts, err
+	}
+	if err := validateGrouping(&opts); err != nil {
+		return nil, opts, err
+	}
+	if err := validateSocialCard(&opts.SocialCard); err != nil {
+		return nil, opts, err
+	}
+	domain, err := s.normalizeDomain(opts.Domain)
+	if err != nil {
+		return nil, opts, err
+	}
+	opts.Domain = domain
+
+	// Empreinte de l'URL canonique, utilisée pour retrouver les liens vers la même destination
+	urlHash, err := urlnorm.Hash(longURL)
+	if err != nil {
+		return nil, opts, fmt.Errorf("%w: %v", policy.ErrInvalidURL, err)
+	}
+
+	// Crée une nouvelle instance du modèle Link
+	link := &models.Link{
+		Domain:    domain,
+		LongURL:   longURL,
+		URLHash:   urlHash,
+		CreatedAt: time.Now(),
+		IsActive:  true,
+		MaxClicks: opts.MaxClicks,
+
+		StartsAt:    opts.StartsAt,
+		Schedule:    opts.Schedule,
+		FallbackURL: opts.FallbackURL,
+
+		DeviceRules: opts.DeviceRules,
+		GeoRules:    opts.GeoRules,
+		Variants:    opts.Variants,
+
+		UTM:           opts.UTM,
+		ForwardQuery:  opts.ForwardQuery,
+		QueryConflict: opts.QueryConflict,
+
+		SocialCard: opts.SocialCard,
+	}
+
+	// Hache le mot de passe éventuel : seul le hachage est conservé en base
+	if opts.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(opts.Password), bcrypt.DefaultCost)
 		if err != nil {
-			return "", fmt.Errorf("failed to generate random number: %w", err)
+			return nil, opts, fmt.Errorf("failed to hash password: %w", err)
 		}
-		// Sélectionne un caractère aléatoire du charset
-		result[i] = charset[randomIndex.Int64()]
+		link.PasswordHash = string(hash)
 	}
 
-	return string(result), nil
+	return link, opts, nil
 }
 
-// CreateLink crée un nouveau lien raccourci.
-// Il génère un code court unique, puis persiste le lien dans la base de données.
-func (s *LinkService) CreateLink(longURL string) (*models.Link, error) {
-	var shortCode string // Variable pour stocker le code court généré
-	const maxRetries = 5 // Nombre maximum de tentatives pour trouver un code unique
-	var code string      // Variable temporaire pour chaque tentative de génération
-	var err error        // Variable pour capturer les erreurs
+// saveLink attribue un code court unique au lien, l'associe à ses étiquettes et à sa campagne
+// puis le persiste. Les dépôts sont passés en paramètre pour permettre l'enregistrement
+// dans une transaction (voir CreateLinks).
+func (s *LinkService) saveLink(linkRepo repository.LinkRepository, tagRepo repository.TagRepository, link *models.Link, opts CreateLinkOptions) error {
+	shortCode, err := s.generateUniqueShortCode(linkRepo, link.Domain)
+	if err != nil {
+		return err
+	}
+	link.ShortCode = shortCode
+
+	// Étiquettes, campagne et dossier
+	if err := attachGrouping(tagRepo, link, opts); err != nil {
+		return err
+	}
+
+	// Persiste le nouveau lien dans la base de données via le repository
+	if err := linkRepo.CreateLink(link); err != nil {
+		return fmt.Errorf("failed to save link to database: %w", err)
+	}
+	return nil
+}
+
+// generateUniqueShortCode génère un code court absent de la base de données pour le domaine donné.
+// Chaque tentative est signalée au générateur, qui allonge ses codes si les collisions deviennent fréquentes.
+func (s *LinkService) generateUniqueShortCode(linkRepo repository.LinkRepository, domain string) (string, error) {
+	const maxRetries = 10 // Nombre maximum de tentatives pour trouver un code unique
 
 	for i := 0; i < maxRetries; i++ {
-		// Génère un code de 6 caractères
-		code, err = s.GenerateShortCode(6)
+		code, err := s.codes.Generate(linkRepo)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate short code: %w", err)
+			return "", fmt.Errorf("failed to generate short code: %w", err)
 		}
 
-		// Vérifie si le code généré existe déjà en base de données
-		_, err = s.linkRepo.GetLinkByShortCode(code)
+		// Vérifie si le code généré existe déjà en base de données (liens à la corbeille compris)
+		exists, err := linkRepo.ShortCodeExists(domain, code)
 		if err != nil {
-			// Si l'erreur est 'record not found' de GORM, cela signifie que le code est unique.
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				shortCode = code // Le code est unique, on peut l'utiliser
-				break            // Sort de la boucle de retry
-			}
-			// Si c'est une autre erreur de base de données, retourne l'erreur.
-			return nil, fmt.Errorf("database error checking short code uniqueness: %w", err)
+			return "", fmt.Errorf("database error checking short code uniqueness: %w", err)
+		}
+		if !exists {
+			s.codes.Observe(false)
+			return code, nil // Le code est unique, on peut l'utiliser
 		}
 
-		// Si aucune erreur (le code a été trouvé), cela signifie une collision.
+		// Le code a été trouvé : collision, une nouvelle tentative est effectuée
+		s.codes.Observe(true)
 		log.Printf("Short code '%s' already exists, retrying generation (%d/%d)...", code, i+1, maxRetries)
-		// La boucle continuera pour générer un nouveau code.
 	}
+	return "", errors.New("failed to generate unique short code after maximum retries")
+}
 
-	// Si après toutes les tentatives, aucun code unique n'a été trouvé
-	if shortCode == "" {
-		return nil, errors.New("failed to generate unique short code after maximum retries")
+// validateDestination vérifie une URL de destination avec la politique de sécurité
+// (schéma, domaine, adresse interne) et les listes de blocage (phishing, malware).
+func (s *LinkService) validateDestination(rawURL string) error {
+	if s.policy != nil {
+		if err := s.policy.ValidateURL(rawURL); err != nil {
+			return err
+		}
 	}
+	return s.checkReputation(rawURL)
+}
 
-	// Crée une nouvelle instance du modèle Link
-	link := &models.Link{
-		ShortCode: shortCode,
-		LongURL:   longURL,
-		CreatedAt: time.Now(),
-		IsActive:  true,
+// checkReputation vérifie une URL de destination contre les listes de blocage.
+func (s *LinkService) checkReputation(rawURL string) error {
+	if s.checker != nil {
+		if verdict := s.checker.Check(rawURL); verdict.Flagged {
+			return fmt.Errorf("%w: %s", ErrDestinationFlagged, verdict.Reason)
+		}
 	}
+	return nil
+}
 
-	// Persiste le nouveau lien dans la base de données via le repository
-	if err := s.linkRepo.CreateLink(link); err != nil {
-		return nil, fmt.Errorf("failed to save link to database: %w", err)
+// validateDeviceRules vérifie les cibles et la destination de chaque règle par appareil.
+// Les destinations peuvent être des liens profonds d'application (voir DestinationPolicy.ValidateAppURL).
+func (s *LinkService) validateDeviceRules(rules []models.DeviceRule) error {
+	for i := range rules {
+		rule := &rules[i]
+		if len(rule.Targets) == 0 {
+			return fmt.Errorf("%w: device rule %d has no target", ErrInvalidRoutingRule, i+1)
+		}
+		for j, target := range rule.Targets {
+			target = strings.ToLower(strings.TrimSpace(target))
+			if !useragent.IsKnownTarget(target) {
+				return fmt.Errorf("%w: device rule %d: unknown target %q", ErrInvalidRoutingRule, i+1, target)
+			}
+			rule.Targets[j] = target
+		}
+		rule.URL = strings.TrimSpace(rule.URL)
+		if rule.URL == "" {
+			return fmt.Errorf("%w: device rule %d has no URL", ErrInvalidRoutingRule, i+1)
+		}
+		if s.policy != nil {
+			if err := s.policy.ValidateAppURL(rule.URL); err != nil {
+				return fmt.Errorf("device rule %d: %w", i+1, err)
+			}
+		}
+		if err := s.checkReputation(rule.URL); err != nil {
+			return fmt.Errorf("device rule %d: %w", i+1, err)
+		}
 	}
+	return nil
+}
 
-	// Retourne le lien créé
-	return link, nil
+// validateGeoRules vérifie les codes pays et la destination de chaque règle géographique.
+func (s *LinkService) validateGeoRules(rules []models.GeoRule) error {
+	for i := range rules {
+		rule := &rules[i]
+		if len(rule.Countries) == 0 {
+			return fmt.Errorf("%w: geo rule %d has no country", ErrInvalidRoutingRule, i+1)
+		}
+		for j, country := range rule.Countries {
+			country = strings.ToUpper(strings.TrimSpace(country))
+			if len(country) != 2 {
+				return fmt.Errorf("%w: geo rule %d: invalid country code %q", ErrInvalidRoutingRule, i+1, country)
+			}
+			rule.Countries[j] = country
+		}
+		if err := s.validateDestination(rule.URL); err != nil {
+			return fmt.Errorf("geo rule %d: %w", i+1, err)
+		}
+	}
+	return nil
 }
 
-// GetLinkByShortCode récupère un lien via son code court.
-func (s *LinkService) GetLinkByShortCode(shortCode string) (*models.Link, error) {
+// validateVariants vérifie les variantes d'un test A/B : au moins deux variantes,
+// noms uniques (attribués "A", "B"... s'ils sont absents), poids positifs et destinations valides.
+func (s *LinkService) validateVariants(variants []models.Variant) error {
+	if len(variants) == 0 {
+		return nil
+	}
+	if len(variants) < 2 {
+		return fmt.Errorf("%w: an A/B test needs at least two variants", ErrInvalidRoutingRule)
+	}
+
+	names := make(map[string]bool, len(variants))
+	totalWeight := 0
+	for i := range variants {
+		variant := &variants[i]
+		variant.Name = strings.TrimSpace(variant.Name)
+		if variant.Name == "" {
+			variant.Name = variantLabel(i)
+		}
+		if len(variant.Name) > 64 {
+			return fmt.Errorf("%w: variant %d: name is too long", ErrInvalidRoutingRule, i+1)
+		}
+		if names[variant.Name] {
+			return fmt.Errorf("%w: duplicate variant name %q", ErrInvalidRoutingRule, variant.Name)
+		}
+		names[variant.Name] = true
+
+		if variant.Weight < 0 {
+			return fmt.Errorf("%w: variant %q: weight must be >= 0", ErrInvalidRoutingRule, variant.Name)
+		}
+		totalWeight += variant.Weight
+
+		if err := s.validateDestination(variant.URL); err != nil {
+			return fmt.Errorf("variant %q: %w", variant.Name, err)
+		}
+	}
+	if totalWeight == 0 {
+		return fmt.Errorf("%w: at least one variant must have a positive weight", ErrInvalidRoutingRule)
+	}
+	return nil
+}
+
+// validateQueryOptions normalise les paramètres UTM et la règle de conflit des paramètres transmis.
+func validateQueryOptions(opts *CreateLinkOptions) error {
+	if utm := opts.UTM; utm != nil {
+		for _, field := range []*string{&utm.Source, &utm.Medium, &utm.Campaign, &utm.Term, &utm.Content} {
+			*field = strings.TrimSpace(*field)
+			if len(*field) > 255 {
+				return fmt.Errorf("%w: UTM values are limited to 255 characters", ErrInvalidQueryOption)
+			}
+		}
+		if *utm == (models.UTM{}) {
+			opts.UTM = nil
+		}
+	}
+
+	opts.QueryConflict = strings.ToLower(strings.TrimSpace(opts.QueryConflict))
+	if !models.IsValidQueryConflict(opts.QueryConflict) {
+		return fmt.Errorf("%w: query conflict must be %s, %s or %s", ErrInvalidQueryOption,
+			models.QueryConflictKeep, models.QueryConflictOverride, models.QueryConflictAppend)
+	}
+	if opts.QueryConflict == "" {
+		opts.QueryConflict = models.QueryConflictKeep
+	}
+	return nil
+}
+
+// variantLabel retourne le nom par défaut de la i-ème variante : A, B, ..., Z, V27, V28...
+func variantLabel(i int) string {
+	if i < 26 {
+		return string(rune('A' + i))
+	}
+	return fmt.Sprintf("V%d", i+1)
+}
+
+// GetLinkByShortCode récupère un lien via son domaine (vide = domaine par défaut) et son code court.
+func (s *LinkService) GetLinkByShortCode(domain, shortCode string) (*models.Link, error) {
 	code := strings.TrimSpace(shortCode)
 	if code == "" {
 		return nil, ErrShortCodeRequired
 	}
+	domain, err := s.normalizeDomain(domain)
+	if err != nil {
+		return nil, err
+	}
 
-	link, err := s.linkRepo.GetLinkByShortCode(code)
+	link, err := s.linkRepo.GetLinkByShortCode(domain, code)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrLinkNotFound
+			return nil, s.notFoundError(domain, code)
 		}
 		return nil, err
 	}
@@ -122,20 +460,52 @@ func (s *LinkService) GetLinkByShortCode(shortCode string) (*models.Link, error)
 	return link, nil
 }
 
+// VerifyPassword vérifie le mot de passe soumis pour un lien protégé.
+// Un lien sans mot de passe est toujours accessible.
+func (s *LinkService) VerifyPassword(link *models.Link, password string) error {
+	if !link.IsPasswordProtected() {
+		return nil
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)); err != nil {
+		return ErrInvalidPassword
+	}
+	return nil
+}
+
+// ConsumeClick consomme une redirection d'un lien limité par MaxClicks.
+// Le compteur est incrémenté atomiquement en base, indépendamment de l'enregistrement
+// asynchrone des clics, afin que des redirections concurrentes ne dépassent jamais la limite.
+// Retourne ErrLinkExhausted si le lien n'a plus de redirection disponible.
+func (s *LinkService) ConsumeClick(link *models.Link) error {
+	if link.MaxClicks <= 0 {
+		return nil
+	}
+
+	reserved, err := s.linkRepo.ReserveClick(link.ID)
+	if err != nil {
+		return fmt.Errorf("failed to reserve click: %w", err)
+	}
+	if !reserved {
+		link.ClickCount = link.MaxClicks
+		return ErrLinkExhausted
+	}
+	link.ClickCount++
+	return nil
+}
+
 // GetLinkStats récupère les statistiques pour un lien donné (nombre total de clics).
+// Les clics par variante A/B sont fournis par ClickService.GetVariantStats.
 // Il interagit avec le LinkRepository pour obtenir le lien, puis compte les clics.
-func (s *LinkService) GetLinkStats(shortCode string) (*models.Link, int, error) {
-	link, err := s.GetLinkByShortCode(shortCode)
+func (s *LinkService) GetLinkStats(domain, shortCode string) (*LinkStats, error) {
+	link, err := s.GetLinkByShortCode(domain, shortCode)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	// Compte le nombre de clics pour ce LinkID
 	clickCount, err := s.linkRepo.CountClicksByLinkID(link.ID)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-
-	// Retourne le lien, le nombre de clics et aucune erreur
-	return link, clickCount, nil
+	return &LinkStats{Link: link, TotalClicks: clickCount}, nil
 }
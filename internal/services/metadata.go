@@ -0,0 +1,26 @@
+package services
+
+import (
+	"log"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+)
+
+// SetMetadataQueue définit le channel des demandes de récupération des métadonnées de destination
+// (voir workers.StartMetadataWorkers). Sans channel, les métadonnées ne sont pas récupérées.
+func (s *LinkService) SetMetadataQueue(queue chan<- models.MetadataJob) {
+	s.metadataQueue = queue
+}
+
+// enqueueMetadata demande la récupération asynchrone des métadonnées de la destination d'un lien.
+// L'envoi n'est jamais bloquant : si le channel est plein, la demande est abandonnée.
+func (s *LinkService) enqueueMetadata(link *models.Link) {
+	if s.metadataQueue == nil {
+		return
+	}
+	select {
+	case s.metadataQueue <- models.MetadataJob{LinkID: link.ID, LongURL: link.LongURL}:
+	default:
+		log.Printf("Warning: metadata queue is full, skipping metadata for %s.", link.ShortCode)
+	}
+}
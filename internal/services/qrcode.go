@@ -0,0 +1,42 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/qr"
+)
+
+// QRCode est l'image d'un QR code et l'URL qu'il encode.
+type QRCode struct {
+	URL         string // URL courte encodée, marquée comme provenant d'un QR code ("?src=qr")
+	Image       []byte
+	ContentType string
+}
+
+// GenerateQRCode produit le QR code de l'URL courte d'un lien (sur son domaine). L'URL encodée porte le paramètre
+// "src=qr" afin que les scans soient comptés séparément dans les statistiques.
+// Des options invalides retournent une erreur qr.ErrInvalidOptions.
+func (s *LinkService) GenerateQRCode(domain, shortCode string, opts qr.Options) (*QRCode, error) {
+	opts, err := opts.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.GetLinkByShortCode(domain, shortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	content := s.ShortURL(link) + "?" + url.Values{models.SourceQueryParam: {models.ClickSourceQR}}.Encode()
+	image, err := qr.Render(content, opts)
+	if err != nil {
+		if errors.Is(err, qr.ErrInvalidOptions) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to render QR code: %w", err)
+	}
+	return &QRCode{URL: content, Image: image, ContentType: qr.ContentType(opts.Format)}, nil
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"maps"
+	"math/rand/v2"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/useragent"
+)
+
+// Visitor décrit le visiteur d'une redirection ; il sert à choisir la destination.
+type Visitor struct {
+	IPAddress string     // Adresse IP du client (c.ClientIP())
+	UserAgent string     // User-Agent du client
+	Variant   string     // Variante A/B déjà attribuée au visiteur (cookie), vide à la première visite
+	Query     url.Values // Paramètres de la requête du visiteur (transmis si le lien l'autorise)
+}
+
+// Resolution est la destination retenue pour une redirection et le contexte qui a servi à la choisir.
+type Resolution struct {
+	URL      string // URL vers laquelle rediriger
+	Country  string // Code pays résolu depuis l'adresse IP (vide si inconnu)
+	Platform string // Plateforme déduite du User-Agent
+	Device   string // Catégorie d'appareil déduite du User-Agent
+	Variant  string // Variante A/B retenue (vide si le visiteur a été redirigé par une règle)
+	Source   string // Provenance du clic (ex: "qr"), lue dans le paramètre "src" de l'URL courte
+}
+
+// ResolveDestination choisit l'URL de destination d'un lien selon les règles de routage :
+// la première règle par appareil correspondant au User-Agent, puis la première règle
+// géographique correspondant au pays du visiteur, puis la variante A/B du visiteur, sinon LongURL.
+// Les paramètres UTM et les paramètres transmis sont ensuite ajoutés à l'URL retenue ;
+// le paramètre de provenance ("src=qr") est consommé et n'est pas transmis à la destination.
+func (s *LinkService) ResolveDestination(link *models.Link, visitor Visitor) Resolution {
+	resolution := s.routeVisitor(link, visitor)
+	query := visitor.Query
+	if source := query.Get(models.SourceQueryParam); models.IsKnownClickSource(source) {
+		resolution.Source = source
+		query = maps.Clone(query)
+		query.Del(models.SourceQueryParam)
+	}
+	resolution.URL = applyQueryParameters(link, resolution.URL, query)
+	return resolution
+}
+
+// routeVisitor applique les règles de routage et retourne la destination brute.
+func (s *LinkService) routeVisitor(link *models.Link, visitor Visitor) Resolution {
+	ua := useragent.Parse(visitor.UserAgent)
+	resolution := Resolution{
+		URL:      link.LongURL,
+		Country:  s.geo.Country(visitor.IPAddress),
+		Platform: ua.Platform,
+		Device:   ua.Device,
+	}
+
+	for _, rule := range link.DeviceRules {
+		if rule.Matches(resolution.Platform, resolution.Device) {
+			resolution.URL = rule.URL
+			return resolution
+		}
+	}
+
+	if resolution.Country != "" {
+		for _, rule := range link.GeoRules {
+			if rule.Matches(resolution.Country) {
+				resolution.URL = rule.URL
+				return resolution
+			}
+		}
+	}
+
+	if variant := pickVariant(link.Variants, visitor.Variant); variant != nil {
+		resolution.URL = variant.URL
+		resolution.Variant = variant.Name
+	}
+	return resolution
+}
+
+// applyQueryParameters fusionne dans la destination les paramètres UTM du lien (qui remplacent
+// les utm_* éventuellement présents), puis, si ForwardQuery est actif, les paramètres de la
+// requête du visiteur selon la règle de conflit du lien. La requête d'origine de la destination
+// est conservée telle quelle (ordre, encodage) : seuls les paramètres remplacés en sont retirés,
+// et les valeurs ajoutées sont placées à la suite. Les liens profonds d'application
+// (schémas autres que http/https) sont laissés intacts.
+func applyQueryParameters(link *models.Link, destination string, incoming url.Values) string {
+	forward := link.ForwardQuery && len(incoming) > 0
+	if link.UTM == nil && !forward {
+		return destination
+	}
+
+	u, err := url.Parse(destination)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return destination
+	}
+
+	original := u.Query()
+	query := u.Query()
+	for key, values := range link.UTM.Values() {
+		query[key] = values
+	}
+	if forward {
+		for key, values := range incoming {
+			switch {
+			case !query.Has(key), link.QueryConflict == models.QueryConflictOverride:
+				query[key] = values
+			case link.QueryConflict == models.QueryConflictAppend:
+				query[key] = append(query[key], values...)
+			}
+			// QueryConflictKeep : la valeur de la destination est conservée
+		}
+	}
+
+	u.RawQuery = mergeRawQuery(u.RawQuery, original, query)
+	return u.String()
+}
+
+// mergeRawQuery reporte sur la requête brute rawQuery, dont original sont les paramètres, les
+// modifications de query : les paramètres inchangés gardent leur forme d'origine, ceux dont les
+// valeurs ont été remplacées sont retirés, et les valeurs nouvelles sont ajoutées à la fin.
+func mergeRawQuery(rawQuery string, original, query url.Values) string {
+	removed := make(map[string]bool)
+	added := url.Values{}
+	for key, values := range query {
+		old := original[key]
+		switch {
+		case slices.Equal(values, old):
+			// Paramètre inchangé
+		case len(values) > len(old) && slices.Equal(values[:len(old)], old):
+			added[key] = values[len(old):] // Valeurs ajoutées à celles d'origine (QueryConflictAppend)
+		default:
+			if len(old) > 0 {
+				removed[key] = true
+			}
+			added[key] = values
+		}
+	}
+	if len(added) == 0 {
+		return rawQuery
+	}
+
+	var parts []string
+	for _, part := range strings.Split(rawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(part, "=")
+		if key, err := url.QueryUnescape(name); err == nil && removed[key] {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(append(parts, added.Encode()), "&")
+}
+
+// pickVariant retourne la variante déjà attribuée au visiteur si elle existe encore et n'est pas
+// suspendue (poids nul), sinon tire une variante au hasard proportionnellement aux poids.
+func pickVariant(variants []models.Variant, assigned string) *models.Variant {
+	total := 0
+	for i := range variants {
+		if assigned != "" && variants[i].Name == assigned && variants[i].Weight > 0 {
+			return &variants[i]
+		}
+		total += variants[i].Weight
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	n := rand.IntN(total)
+	for i := range variants {
+		if n < variants[i].Weight {
+			return &variants[i]
+		}
+		n -= variants[i].Weight
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
+)
+
+// ReportReasons liste les catégories de signalement acceptées.
+var ReportReasons = []string{"phishing", "malware", "spam", "illegal", "other"}
+
+// ReportService fournit la logique métier des signalements d'abus et de la modération.
+type ReportService struct {
+	reportRepo repository.ReportRepository       // Signalements et actions de modération
+	linkRepo   repository.LinkRepository         // Liens signalés (désactivation)
+	bannedRepo repository.BannedDomainRepository // Domaines bannis
+	audit      *AuditService                     // Journal d'audit des actions de modération (voir SetAuditLog)
+}
+
+// NewReportService crée et retourne une nouvelle instance de ReportService.
+func NewReportService(reportRepo repository.ReportRepository, linkRepo repository.LinkRepository, bannedRepo repository.BannedDomainRepository) *ReportService {
+	return &ReportService{
+		reportRepo: reportRepo,
+		linkRepo:   linkRepo,
+		bannedRepo: bannedRepo,
+	}
+}
+
+// SetAuditLog définit le journal d'audit des actions de modération.
+func (s *ReportService) SetAuditLog(audit *AuditService) {
+	s.audit = audit
+}
+
+// SubmitReport enregistre un signalement d'abus pour un lien court de domain (vide = domaine par défaut).
+func (s *ReportService) SubmitReport(domain, shortCode, reason, details, email, ip string) (*models.Report, error) {
+	code := strings.TrimSpace(shortCode)
+	if code == "" {
+		return nil, ErrShortCodeRequired
+	}
+
+	reason = strings.ToLower(strings.TrimSpace(reason))
+	if !isValidReportReason(reason) {
+		return nil, fmt.Errorf("%w: must be one of %s", ErrInvalidReportReason, strings.Join(ReportReasons, ", "))
+	}
+
+	link, err := s.linkRepo.GetLinkByShortCode(domain, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLinkNotFound
+		}
+		return nil, err
+	}
+
+	report := &models.Report{
+		LinkID:        link.ID,
+		Reason:        reason,
+		Details:       strings.TrimSpace(details),
+		ReporterEmail: strings.TrimSpace(email),
+		ReporterIP:    ip,
+		Status:        models.ReportStatusOpen,
+	}
+	if err := s.reportRepo.CreateReport(report); err != nil {
+		return nil, fmt.Errorf("failed to save report: %w", err)
+	}
+	report.Link = *link
+	return report, nil
+}
+
+// ListReports retourne les signalements, filtrés par statut si status n'est pas vide.
+func (s *ReportService) ListReports(status string) ([]models.Report, error) {
+	return s.reportRepo.ListReports(strings.ToLower(strings.TrimSpace(status)))
+}
+
+// GetReport retourne un signalement et l'historique de ses actions de modération.
+func (s *ReportService) GetReport(id uint) (*models.Report, []models.ModerationAction, error) {
+	report, err := s.reportRepo.GetReportByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrReportNotFound
+		}
+		return nil, nil, err
+	}
+
+	actions, err := s.reportRepo.ListModerationActions(report.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return report, actions, nil
+}
+
+// ApplyAction applique une action de modération à un signalement et la trace dans l'historique :
+//   - disable_link : désactive le lien signalé ;
+//   - ban_domain   : bannit le domaine de destination et désactive le lien, ainsi que
+//     tous les autres liens actifs vers ce domaine (sous-domaines compris) ;
+//   - dismiss      : rejette le signalement.
+func (s *ReportService) ApplyAction(reportID uint, action, actor, note string) (*models.Report, error) {
+	report, _, err := s.GetReport(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	action = strings.ToLower(strings.TrimSpace(action))
+	previousStatus := report.Status
+	// Lien à la corbeille ou purgé : il n'est pas chargé avec le signalement et ne peut être désactivé
+	if report.Link.ID == 0 && (action == models.ModerationActionDisableLink || action == models.ModerationActionBanDomain) {
+		return nil, ErrLinkNotFound
+	}
+
+	// L'action, ses effets et leurs entrées d'audit sont enregistrés dans une même transaction
+	err = s.linkRepo.WithinTransaction(func(tx repository.Tx) error {
+		switch action {
+		case models.ModerationActionDisableLink:
+			if err := s.disableLink(tx, &report.Link, fmt.Sprintf("moderation: report #%d", report.ID), actor); err != nil {
+				return err
+			}
+			report.Status = models.ReportStatusActioned
+
+		case models.ModerationActionBanDomain:
+			u, err := url.Parse(report.Link.LongURL)
+			if err != nil || u.Hostname() == "" {
+				return fmt.Errorf("cannot extract domain from %q", report.Link.LongURL)
+			}
+			banned := &models.BannedDomain{
+				Domain: u.Hostname(),
+				Reason: fmt.Sprintf("report #%d: %s", report.ID, note),
+				Actor:  actor,
+			}
+			if err := tx.BannedDomains.BanDomain(banned); err != nil {
+				return fmt.Errorf("failed to ban domain: %w", err)
+			}
+			err = s.audit.Record(tx, models.AuditEntry{
+				Actor:      actor,
+				Action:     models.AuditDomainBanned,
+				TargetType: models.AuditTargetDomain,
+				Target:     banned.Domain,
+				Diff:       models.AuditDiff{"banned": {Old: false, New: true}},
+				Note:       banned.Reason,
+			})
+			if err != nil {
+				return err
+			}
+			reason := fmt.Sprintf("moderation: domain %s banned (report #%d)", banned.Domain, report.ID)
+			if err := s.disableLink(tx, &report.Link, reason, actor); err != nil {
+				return err
+			}
+			if err := s.disableDomainLinks(tx, banned.Domain, report.Link.ID, reason, actor); err != nil {
+				return err
+			}
+			report.Status = models.ReportStatusActioned
+
+		case models.ModerationActionDismiss:
+			report.Status = models.ReportStatusDismissed
+
+		default:
+			return fmt.Errorf("%w: %q", ErrInvalidModerationAction, action)
+		}
+
+		now := time.Now()
+		report.ResolvedAt = &now
+		if err := tx.Reports.UpdateReport(report); err != nil {
+			return fmt.Errorf("failed to update report: %w", err)
+		}
+
+		// Trace l'action pour la piste d'audit
+		if err := tx.Reports.CreateModerationAction(&models.ModerationAction{
+			ReportID: report.ID,
+			LinkID:   report.LinkID,
+			Action:   action,
+			Actor:    actor,
+			Note:     note,
+		}); err != nil {
+			return fmt.Errorf("failed to record moderation action: %w", err)
+		}
+		return s.audit.Record(tx, models.AuditEntry{
+			Actor:      actor,
+			Action:     models.AuditReportResolved,
+			TargetType: models.AuditTargetReport,
+			Target:     strconv.FormatUint(uint64(report.ID), 10),
+			Diff: models.AuditDiff{
+				"status": {Old: previousStatus, New: report.Status},
+				"action": {New: action},
+			},
+			Note: note,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// ListBannedDomains retourne les domaines bannis par la modération.
+func (s *ReportService) ListBannedDomains() ([]models.BannedDomain, error) {
+	return s.bannedRepo.ListBannedDomains()
+}
+
+// disableLink désactive un lien en conservant la raison de la désactivation, et journalise la désactivation,
+// dans la transaction tx. Seules les colonnes de désactivation sont écrites : les autres champs
+// du lien chargé (santé, destination) ont pu changer depuis.
+func (s *ReportService) disableLink(tx repository.Tx, link *models.Link, reason, actor string) error {
+	diff := models.AuditDiff{
+		"is_disabled":     {Old: link.IsDisabled, New: true},
+		"disabled_reason": {Old: link.DisabledReason, New: reason},
+	}
+	link.IsDisabled = true
+	link.DisabledReason = reason
+	if err := tx.Links.UpdateDisabled(link); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrLinkNotFound // Mis à la corbeille entre-temps
+		}
+		return fmt.Errorf("failed to disable link: %w", err)
+	}
+	return s.audit.Record(tx, linkEntry(link, models.AuditLinkDisabled, actor, diff, ""))
+}
+
+// disableDomainLinks désactive les liens encore actifs dont la destination est sur un domaine banni
+// (sous-domaines compris), hormis le lien signalé exceptLinkID déjà désactivé.
+func (s *ReportService) disableDomainLinks(tx repository.Tx, domain string, exceptLinkID uint, reason, actor string) error {
+	links, err := tx.Links.ListLinksByDestinationHost(domain)
+	if err != nil {
+		return fmt.Errorf("failed to list links to banned domain: %w", err)
+	}
+	for i := range links {
+		if links[i].ID == exceptLinkID || links[i].IsDisabled {
+			continue
+		}
+		if err := s.disableLink(tx, &links[i], reason, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isValidReportReason indique si la catégorie fait partie de ReportReasons.
+func isValidReportReason(reason string) bool {
+	for _, r := range ReportReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
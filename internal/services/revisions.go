@@ -0,0 +1,123 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/policy"
+	"github.com/Quanghng/url-shortener/internal/repository"
+	"github.com/Quanghng/url-shortener/internal/urlnorm"
+)
+
+// LinkHistory regroupe un lien et l'historique de sa destination.
+type LinkHistory struct {
+	Link        *models.Link
+	OriginalURL string                // Destination à la création du lien (révision 0)
+	Revisions   []models.LinkRevision // Modifications, de la plus ancienne à la plus récente
+}
+
+// UpdateDestination remplace la destination d'un lien. La nouvelle URL est soumise aux mêmes
+// vérifications qu'à la création (politique de destination, réputation) et la modification
+// est tracée dans l'historique du lien avec son auteur.
+func (s *LinkService) UpdateDestination(domain, shortCode, longURL, actor, note string) (*models.Link, *models.LinkRevision, error) {
+	link, err := s.GetLinkByShortCode(domain, shortCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.changeDestination(link, longURL, actor, note, nil)
+}
+
+// GetLinkHistory retourne l'historique des destinations d'un lien.
+func (s *LinkService) GetLinkHistory(domain, shortCode string) (*LinkHistory, error) {
+	link, err := s.GetLinkByShortCode(domain, shortCode)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := s.linkRepo.ListRevisions(link.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	history := &LinkHistory{Link: link, OriginalURL: link.LongURL, Revisions: revisions}
+	if len(revisions) > 0 {
+		history.OriginalURL = revisions[0].OldURL
+	}
+	return history, nil
+}
+
+// RollbackDestination rétablit la destination d'une révision précédente (0 = destination d'origine).
+// Le retour arrière est lui-même une nouvelle révision : l'historique n'est jamais réécrit.
+func (s *LinkService) RollbackDestination(domain, shortCode string, revision int, actor, note string) (*models.Link, *models.LinkRevision, error) {
+	history, err := s.GetLinkHistory(domain, shortCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	if revision < 0 || revision > len(history.Revisions) {
+		return nil, nil, fmt.Errorf("%w: %d", ErrRevisionNotFound, revision)
+	}
+
+	target := history.OriginalURL
+	if revision > 0 {
+		target = history.Revisions[revision-1].NewURL
+	}
+	return s.changeDestination(history.Link, target, actor, note, &revision)
+}
+
+// changeDestination vérifie et enregistre une nouvelle destination, puis relance la récupération
+// des métadonnées de la page.
+func (s *LinkService) changeDestination(link *models.Link, longURL, actor, note string, rollbackOf *int) (*models.Link, *models.LinkRevision, error) {
+	longURL = strings.TrimSpace(longURL)
+	if longURL == link.LongURL {
+		return nil, nil, ErrDestinationUnchanged
+	}
+	if err := s.validateDestination(longURL); err != nil {
+		return nil, nil, err
+	}
+	urlHash, err := urlnorm.Hash(longURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", policy.ErrInvalidURL, err)
+	}
+
+	rev := &models.LinkRevision{
+		OldURL:     link.LongURL,
+		NewURL:     longURL,
+		Actor:      actor,
+		Note:       strings.TrimSpace(note),
+		RollbackOf: rollbackOf,
+	}
+	// L'état établi pour l'ancienne destination est réinitialisé (voir LinkRepository.UpdateDestination)
+	diff := resetHealthDiff(link)
+	link.LongURL, link.URLHash = longURL, urlHash
+	err = s.linkRepo.WithinTransaction(func(tx repository.Tx) error {
+		if err := tx.Links.UpdateDestination(link, rev); err != nil {
+			return fmt.Errorf("failed to update destination: %w", err)
+		}
+		diff["long_url"] = models.AuditChange{Old: rev.OldURL, New: rev.NewURL}
+		diff["revision"] = models.AuditChange{New: rev.Revision}
+		if rollbackOf != nil {
+			diff["rollback_of"] = models.AuditChange{New: *rollbackOf}
+		}
+		return s.audit.Record(tx, linkEntry(link, models.AuditLinkUpdated, actor, diff, rev.Note))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.enqueueMetadata(link)
+	return link, rev, nil
+}
+
+// resetHealthDiff décrit les champs réinitialisés par un changement de destination : accessibilité,
+// statut TLS, signalement de réputation et désactivation qui l'accompagne.
+func resetHealthDiff(link *models.Link) models.AuditDiff {
+	flagged := *link
+	diff := flagged.ClearFlag()
+	if !link.IsActive {
+		diff["is_active"] = models.AuditChange{Old: false, New: true}
+	}
+	if link.TLSStatus != "" {
+		diff["tls_status"] = models.AuditChange{Old: link.TLSStatus, New: ""}
+	}
+	return diff
+}
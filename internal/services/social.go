@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+)
+
+// Longueurs maximales (en caractères) des balises personnalisées de l'aperçu social.
+const (
+	maxSocialTitleLength       = 300
+	maxSocialDescriptionLength = 1000
+	maxSocialImageURLLength    = 2048
+)
+
+// validateSocialCard normalise les balises personnalisées d'un lien et vérifie leur format.
+// L'image n'est jamais téléchargée par le serveur : seule une URL absolue http(s) est exigée.
+func validateSocialCard(card *models.SocialCard) error {
+	card.Title = strings.TrimSpace(card.Title)
+	card.Description = strings.TrimSpace(card.Description)
+	card.ImageURL = strings.TrimSpace(card.ImageURL)
+
+	if utf8.RuneCountInString(card.Title) > maxSocialTitleLength {
+		return fmt.Errorf("%w: title exceeds %d characters", ErrInvalidSocialCard, maxSocialTitleLength)
+	}
+	if utf8.RuneCountInString(card.Description) > maxSocialDescriptionLength {
+		return fmt.Errorf("%w: description exceeds %d characters", ErrInvalidSocialCard, maxSocialDescriptionLength)
+	}
+	if card.ImageURL != "" {
+		u, err := url.Parse(card.ImageURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(card.ImageURL) > maxSocialImageURLLength {
+			return fmt.Errorf("%w: image_url must be an absolute http or https URL", ErrInvalidSocialCard)
+		}
+	}
+	return nil
+}
+
+// SocialCardFor retourne les balises à servir aux robots d'aperçu pour un lien : les valeurs
+// personnalisées, complétées par les métadonnées récupérées sur la destination.
+// Pour un lien protégé par mot de passe, seules les valeurs personnalisées sont utilisées
+// afin de ne rien révéler de la destination ; le titre par défaut est alors l'URL courte.
+func (s *LinkService) SocialCardFor(link *models.Link) models.SocialCard {
+	card := link.SocialCard
+	if !link.IsPasswordProtected() {
+		card.Title = firstNonEmpty(card.Title, link.Metadata.Title)
+		card.Description = firstNonEmpty(card.Description, link.Metadata.Description)
+		card.ImageURL = firstNonEmpty(card.ImageURL, link.Metadata.ImageURL)
+	}
+	card.Title = firstNonEmpty(card.Title, s.ShortURL(link))
+	return card
+}
+
+// firstNonEmpty retourne la première valeur non vide.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
+)
+
+// SetTrashRetention définit la durée de conservation des liens à la corbeille avant leur purge
+// définitive (0 = conservés jusqu'à une purge explicite).
+func (s *LinkService) SetTrashRetention(retention time.Duration) {
+	s.trashRetention = retention
+}
+
+// PurgeDate retourne la date à partir de laquelle un lien à la corbeille sera purgé
+// (nil si le lien n'est pas supprimé ou si la purge automatique est désactivée).
+func (s *LinkService) PurgeDate(link *models.Link) *time.Time {
+	if !link.DeletedAt.Valid || s.trashRetention <= 0 {
+		return nil
+	}
+	date := link.DeletedAt.Time.Add(s.trashRetention)
+	return &date
+}
+
+// DeleteLink met un lien à la corbeille : il ne redirige plus (HTTP 410) mais peut être restauré
+// jusqu'à sa purge, et son code court n'est pas réattribué entre-temps.
+func (s *LinkService) DeleteLink(domain, shortCode, actor string) (*models.Link, error) {
+	link, err := s.GetLinkByShortCode(domain, shortCode)
+	if err != nil {
+		return nil, err
+	}
+	err = s.linkRepo.WithinTransaction(func(tx repository.Tx) error {
+		if err := tx.Links.DeleteLink(link, actor); err != nil {
+			return fmt.Errorf("failed to delete link: %w", err)
+		}
+		return s.audit.Record(tx, linkEntry(link, models.AuditLinkDeleted, actor, models.AuditDiff{"deleted_at": {New: link.DeletedAt.Time}}, ""))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// RestoreLink sort un lien de la corbeille ; il redirige de nouveau.
+// Retourne ErrNotInTrash si le lien existe mais n'est pas supprimé.
+func (s *LinkService) RestoreLink(domain, shortCode, actor string) (*models.Link, error) {
+	code := strings.TrimSpace(shortCode)
+	if code == "" {
+		return nil, ErrShortCodeRequired
+	}
+	domain, err := s.normalizeDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.linkRepo.GetDeletedLink(domain, code)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if _, err := s.linkRepo.GetLinkByShortCode(domain, code); err == nil {
+			return nil, ErrNotInTrash
+		}
+		return nil, ErrLinkNotFound
+	}
+	err = s.linkRepo.WithinTransaction(func(tx repository.Tx) error {
+		if err := tx.Links.RestoreLink(link.ID); err != nil {
+			return fmt.Errorf("failed to restore link: %w", err)
+		}
+		return s.audit.Record(tx, linkEntry(link, models.AuditLinkRestored, actor, models.AuditDiff{"deleted_at": {Old: link.DeletedAt.Time}}, ""))
+	})
+	if err != nil {
+		return nil, err
+	}
+	link.DeletedAt = gorm.DeletedAt{}
+	link.DeletedBy = ""
+	return link, nil
+}
+
+// ListTrash liste les liens à la corbeille, du plus récemment supprimé au plus ancien.
+// Le filtre et la pagination suivent les règles de ListLinks.
+func (s *LinkService) ListTrash(filter repository.LinkFilter) ([]models.Link, error) {
+	filter = NormalizeLinkFilter(filter)
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.linkRepo.ListDeletedLinks(filter)
+}
+
+// PurgeTrash supprime définitivement les liens restés à la corbeille plus longtemps que la durée
+// de conservation, ou tous les liens à la corbeille si all vaut true. Leurs codes courts redeviennent disponibles.
+// Sans durée de conservation, seule la purge complète (all) supprime des liens.
+// Chaque lien purgé est journalisé au nom de actor, dans la transaction de la purge.
+// Retourne le nombre de liens purgés.
+func (s *LinkService) PurgeTrash(all bool, actor string) (int, error) {
+	before := time.Now()
+	note := "trash emptied"
+	if !all {
+		if s.trashRetention <= 0 {
+			return 0, nil
+		}
+		before = before.Add(-s.trashRetention)
+		note = fmt.Sprintf("retention of %d day(s) expired", int(s.trashRetention.Hours()/24))
+	}
+	var purged []models.Link
+	err := s.linkRepo.WithinTransaction(func(tx repository.Tx) error {
+		var err error
+		if purged, err = tx.Links.PurgeDeletedLinks(before); err != nil {
+			return fmt.Errorf("failed to purge trash: %w", err)
+		}
+		for i := range purged {
+			link := &purged[i]
+			err := s.audit.Record(tx, linkEntry(link, models.AuditLinkPurged, actor, models.AuditDiff{
+				"long_url":   {Old: link.LongURL},
+				"deleted_at": {Old: link.DeletedAt.Time},
+			}, note))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(purged), nil
+}
+
+// notFoundError distingue un code inconnu (ErrLinkNotFound) d'un lien à la corbeille (ErrLinkDeleted).
+func (s *LinkService) notFoundError(domain, shortCode string) error {
+	if _, err := s.linkRepo.GetDeletedLink(domain, shortCode); err == nil {
+		return ErrLinkDeleted
+	}
+	return ErrLinkNotFound
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/policy"
+	"github.com/Quanghng/url-shortener/internal/repository"
+	"github.com/Quanghng/url-shortener/internal/webhooks"
+)
+
+// Longueurs maximales des champs d'un abonnement webhook.
+const (
+	maxWebhookSecretLength      = 100
+	minWebhookSecretLength      = 16
+	maxWebhookDescriptionLength = 255
+)
+
+// WebhookInput décrit un abonnement webhook à créer.
+type WebhookInput struct {
+	URL         string   // URL de réception des événements (http ou https)
+	Secret      string   // Secret de signature (vide = généré)
+	Events      []string // Événements souscrits (voir models.WebhookEvents)
+	Description string   // Description libre
+}
+
+// DeliveryQuery décrit une consultation du journal des livraisons d'un abonnement.
+type DeliveryQuery struct {
+	Status string // pending, succeeded ou failed (vide = tous)
+	Limit  int
+	Offset int
+}
+
+// WebhookService gère les abonnements webhook et le journal de leurs livraisons.
+type WebhookService struct {
+	webhookRepo repository.WebhookRepository
+	dispatcher  *webhooks.Dispatcher      // Envoi des livraisons (nil = webhooks désactivés)
+	policy      *policy.DestinationPolicy // Politique de validation des URLs de réception (optionnelle)
+	audit       *AuditService             // Journal d'audit des abonnements (voir SetAuditLog)
+}
+
+// NewWebhookService crée et retourne une nouvelle instance de WebhookService.
+// dispatcher peut être nil (webhooks.enabled = false) : les abonnements restent gérables,
+// mais aucune livraison n'est effectuée.
+func NewWebhookService(webhookRepo repository.WebhookRepository, dispatcher *webhooks.Dispatcher) *WebhookService {
+	return &WebhookService{webhookRepo: webhookRepo, dispatcher: dispatcher}
+}
+
+// SetDestinationPolicy définit la politique appliquée aux URLs de réception :
+// un webhook ne peut pas cibler une adresse interne.
+func (s *WebhookService) SetDestinationPolicy(p *policy.DestinationPolicy) {
+	s.policy = p
+}
+
+// SetAuditLog définit le journal d'audit des créations et suppressions d'abonnements.
+func (s *WebhookService) SetAuditLog(audit *AuditService) {
+	s.audit = audit
+}
+
+// CreateSubscription valide et enregistre un abonnement. Un secret est généré s'il n'est pas fourni.
+func (s *WebhookService) CreateSubscription(input WebhookInput, actor string) (*models.WebhookSubscription, error) {
+	sub := &models.WebhookSubscription{
+		URL:         strings.TrimSpace(input.URL),
+		Secret:      strings.TrimSpace(input.Secret),
+		Description: strings.TrimSpace(input.Description),
+	}
+	if err := s.validateURL(sub.URL); err != nil {
+		return nil, err
+	}
+	events, err := normalizeWebhookEvents(input.Events)
+	if err != nil {
+		return nil, err
+	}
+	sub.Events = events
+	if len(sub.Description) > maxWebhookDescriptionLength {
+		return nil, fmt.Errorf("%w: description must not exceed %d characters", ErrInvalidWebhook, maxWebhookDescriptionLength)
+	}
+	switch {
+	case sub.Secret == "":
+		if sub.Secret, err = webhooks.NewSecret(); err != nil {
+			return nil, err
+		}
+	case len(sub.Secret) < minWebhookSecretLength || len(sub.Secret) > maxWebhookSecretLength:
+		return nil, fmt.Errorf("%w: secret must be between %d and %d characters", ErrInvalidWebhook,
+			minWebhookSecretLength, maxWebhookSecretLength)
+	}
+
+	err = s.webhookRepo.WithinTransaction(func(tx repository.Tx) error {
+		if err := tx.Webhooks.CreateSubscription(sub); err != nil {
+			return err
+		}
+		return s.audit.Record(tx, webhookEntry(sub, models.AuditWebhookCreated, actor, models.AuditDiff{
+			"url":    {New: sub.URL},
+			"events": {New: sub.Events},
+		}, sub.Description))
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.dispatcher.InvalidateSubscriptions()
+	return sub, nil
+}
+
+// ListSubscriptions retourne tous les abonnements.
+func (s *WebhookService) ListSubscriptions() ([]models.WebhookSubscription, error) {
+	return s.webhookRepo.ListSubscriptions()
+}
+
+// DeleteSubscription supprime un abonnement. Son journal de livraisons est conservé.
+func (s *WebhookService) DeleteSubscription(id uint, actor string) error {
+	sub, err := s.getSubscription(id)
+	if err != nil {
+		return err
+	}
+	err = s.webhookRepo.WithinTransaction(func(tx repository.Tx) error {
+		if err := tx.Webhooks.DeleteSubscription(sub.ID); err != nil {
+			return err
+		}
+		return s.audit.Record(tx, webhookEntry(sub, models.AuditWebhookDeleted, actor, models.AuditDiff{
+			"url":    {Old: sub.URL},
+			"events": {Old: sub.Events},
+		}, ""))
+	})
+	if err != nil {
+		return err
+	}
+	s.dispatcher.InvalidateSubscriptions()
+	return nil
+}
+
+// ListDeliveries retourne le journal des livraisons d'un abonnement, de la plus récente
+// à la plus ancienne (50 par défaut, 500 au plus).
+func (s *WebhookService) ListDeliveries(subscriptionID uint, query DeliveryQuery) ([]models.WebhookDelivery, error) {
+	if _, err := s.getSubscription(subscriptionID); err != nil {
+		return nil, err
+	}
+	filter := repository.DeliveryFilter{
+		SubscriptionID: subscriptionID,
+		Status:         strings.ToLower(strings.TrimSpace(query.Status)),
+		Limit:          query.Limit,
+		Offset:         query.Offset,
+	}
+	switch filter.Status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed:
+	default:
+		return nil, fmt.Errorf("%w: status must be %s, %s or %s", ErrInvalidDeliveryQuery,
+			models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.webhookRepo.ListDeliveries(filter)
+}
+
+// Redeliver programme une nouvelle livraison d'un événement déjà livré ou en échec :
+// même identifiant d'événement et même contenu, signé au moment de l'envoi.
+func (s *WebhookService) Redeliver(subscriptionID, deliveryID uint, actor string) (*models.WebhookDelivery, error) {
+	if s.dispatcher == nil {
+		return nil, ErrWebhooksDisabled
+	}
+	sub, err := s.getSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	original, err := s.webhookRepo.GetDelivery(deliveryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	if original.SubscriptionID != sub.ID {
+		return nil, ErrDeliveryNotFound
+	}
+
+	var delivery *models.WebhookDelivery
+	err = s.webhookRepo.WithinTransaction(func(tx repository.Tx) error {
+		var err error
+		if delivery, err = s.dispatcher.Redeliver(tx.Webhooks, original); err != nil {
+			return err
+		}
+		return s.audit.Record(tx, webhookEntry(sub, models.AuditWebhookRedelivered, actor, models.AuditDiff{
+			"event":    {New: original.Event},
+			"event_id": {New: original.EventID},
+			"delivery": {Old: original.ID, New: delivery.ID},
+		}, ""))
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.dispatcher.Enqueue(delivery.ID)
+	return delivery, nil
+}
+
+// getSubscription récupère un abonnement, ErrWebhookNotFound s'il n'existe pas.
+func (s *WebhookService) getSubscription(id uint) (*models.WebhookSubscription, error) {
+	sub, err := s.webhookRepo.GetSubscription(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return sub, nil
+}
+
+// validateURL vérifie l'URL de réception : http ou https, et conforme à la politique de destination.
+func (s *WebhookService) validateURL(rawURL string) error {
+	if rawURL == "" {
+		return fmt.Errorf("%w: url is required", ErrInvalidWebhook)
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	if s.policy != nil {
+		if err := s.policy.ValidateURL(rawURL); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+		}
+	}
+	return nil
+}
+
+// normalizeWebhookEvents valide les événements souscrits, en minuscules et sans doublon.
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool, len(events))
+	for _, event := range events {
+		event = strings.ToLower(strings.TrimSpace(event))
+		if event == "" || seen[event] {
+			continue
+		}
+		known := false
+		for _, e := range models.WebhookEvents {
+			known = known || e == event
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: unknown event %q (expected %s)", ErrInvalidWebhook, event,
+				strings.Join(models.WebhookEvents, ", "))
+		}
+		seen[event] = true
+		normalized = append(normalized, event)
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("%w: at least one event is required (%s)", ErrInvalidWebhook,
+			strings.Join(models.WebhookEvents, ", "))
+	}
+	return normalized, nil
+}
+
+// webhookEntry construit une entrée d'audit portant sur un abonnement webhook.
+func webhookEntry(sub *models.WebhookSubscription, action, actor string, diff models.AuditDiff, note string) models.AuditEntry {
+	return models.AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		TargetType: models.AuditTargetWebhook,
+		Target:     strconv.FormatUint(uint64(sub.ID), 10),
+		Diff:       diff,
+		Note:       note,
+	}
+}
+
+// MaskSecret retourne les derniers caractères d'un secret, pour l'identifier sans l'exposer.
+func MaskSecret(secret string) string {
+	if len(secret) <= 4 {
+		return "…"
+	}
+	return "…" + secret[len(secret)-4:]
+}
+
+// SetWebhooks définit la publication des événements link.created aux webhooks abonnés.
+func (s *LinkService) SetWebhooks(dispatcher *webhooks.Dispatcher) {
+	s.webhooks = dispatcher
+}
+
+// publishLinkCreated publie la création d'un lien aux webhooks abonnés.
+func (s *LinkService) publishLinkCreated(link *models.Link) {
+	s.webhooks.Publish(models.WebhookLinkCreated, models.WebhookLinkData{
+		ShortCode:    link.ShortCode,
+		Domain:       link.Domain,
+		FullShortURL: s.ShortURL(link),
+		LongURL:      link.LongURL,
+		CreatedAt:    link.CreatedAt.UTC(),
+	})
+}
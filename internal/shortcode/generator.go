@@ -0,0 +1,192 @@
+// Package shortcode fournit les stratégies de génération des codes courts :
+// aléatoire, séquentielle et prononçable.
+package shortcode
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/Quanghng/url-shortener/internal/config"
+)
+
+// Stratégies de génération reconnues (clé shortcode.strategy).
+const (
+	StrategyRandom        = "random"
+	StrategySequential    = "sequential"
+	StrategyPronounceable = "pronounceable"
+)
+
+// Encodages des identifiants de la stratégie séquentielle (clé shortcode.encoding).
+const (
+	EncodingBase62     = "base62"     // Identifiant en base N complété à gauche : codes consécutifs
+	EncodingObfuscated = "obfuscated" // Permutation dérivée du sel (à la Hashids) : codes non devinables
+)
+
+// Base62 est l'alphabet par défaut des codes courts.
+const Base62 = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Ambiguous liste les caractères retirés de l'alphabet avec shortcode.exclude_ambiguous.
+const Ambiguous = "0O1lI"
+
+// MaxLength est la longueur maximale d'un code court (taille de la colonne links.short_code).
+const MaxLength = 10
+
+// Paramètres par défaut de l'allongement automatique.
+const (
+	DefaultLength          = 6
+	DefaultGrowthThreshold = 0.1
+	growthWindow           = 100 // Nombre de tentatives observées avant de réévaluer le taux de collision
+	maxConsecutive         = 5   // Collisions consécutives provoquant l'allongement immédiat (espace saturé)
+)
+
+// ErrInvalidConfig est retournée lorsque la section shortcode de la configuration est invalide.
+var ErrInvalidConfig = errors.New("invalid shortcode configuration")
+
+// Sequence fournit des valeurs croissantes et uniques, persistées (voir repository.LinkRepository).
+type Sequence interface {
+	NextSequenceValue(name string) (uint64, error)
+}
+
+// Generator produit des codes courts candidats. L'unicité est vérifiée par l'appelant,
+// qui signale chaque résultat avec Observe pour permettre l'allongement automatique des codes.
+type Generator interface {
+	// Generate retourne un code candidat ; seq n'est utilisée que par la stratégie séquentielle.
+	Generate(seq Sequence) (string, error)
+	// Observe signale si le dernier code généré était déjà utilisé.
+	Observe(collided bool)
+}
+
+// New construit le générateur décrit par la configuration.
+func New(cfg config.ShortCodeConfig) (Generator, error) {
+	alphabet, err := buildAlphabet(cfg.Alphabet, cfg.ExcludeAmbiguous)
+	if err != nil {
+		return nil, err
+	}
+	length := cfg.Length
+	if length == 0 {
+		length = DefaultLength
+	}
+	maxLength := cfg.MaxLength
+	if maxLength == 0 {
+		maxLength = MaxLength
+	}
+	if length < 1 || maxLength < length || maxLength > MaxLength {
+		return nil, fmt.Errorf("%w: length must be between 1 and max_length, max_length at most %d", ErrInvalidConfig, MaxLength)
+	}
+	threshold := cfg.GrowthThreshold
+	if threshold <= 0 || threshold >= 1 {
+		threshold = DefaultGrowthThreshold
+	}
+
+	switch strings.ToLower(cfg.Strategy) {
+	case "", StrategyRandom:
+		return &randomGenerator{alphabet: alphabet, growth: newGrowth(length, maxLength, threshold)}, nil
+	case StrategyPronounceable:
+		return newPronounceableGenerator(cfg.ExcludeAmbiguous, newGrowth(length, maxLength, threshold)), nil
+	case StrategySequential:
+		return newSequentialGenerator(alphabet, length, cfg.Encoding, cfg.Salt)
+	}
+	return nil, fmt.Errorf("%w: unknown strategy %q (random, sequential, pronounceable)", ErrInvalidConfig, cfg.Strategy)
+}
+
+// buildAlphabet valide l'alphabet configuré (Base62 par défaut) et retire les caractères ambigus.
+// Seuls les caractères sûrs dans un chemin d'URL sont acceptés ("+" est réservé à l'aperçu des liens).
+func buildAlphabet(alphabet string, excludeAmbiguous bool) (string, error) {
+	if alphabet == "" {
+		alphabet = Base62
+	}
+	seen := make(map[rune]bool, len(alphabet))
+	var b strings.Builder
+	for _, r := range alphabet {
+		safe := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_'
+		if !safe {
+			return "", fmt.Errorf("%w: alphabet may only contain letters, digits, '-' and '_'", ErrInvalidConfig)
+		}
+		if seen[r] || (excludeAmbiguous && strings.ContainsRune(Ambiguous, r)) {
+			continue
+		}
+		seen[r] = true
+		b.WriteRune(r)
+	}
+	if b.Len() < 16 {
+		return "", fmt.Errorf("%w: alphabet needs at least 16 distinct characters", ErrInvalidConfig)
+	}
+	return b.String(), nil
+}
+
+// RandomString retourne une chaîne aléatoire de length caractères de l'alphabet (crypto/rand).
+func RandomString(alphabet string, length int) (string, error) {
+	result := make([]byte, length)
+	alphabetLen := big.NewInt(int64(len(alphabet)))
+	for i := range result {
+		index, err := rand.Int(rand.Reader, alphabetLen)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate random number: %w", err)
+		}
+		result[i] = alphabet[index.Int64()]
+	}
+	return string(result), nil
+}
+
+// growth allonge les codes d'un caractère lorsque le taux de collision observé dépasse le seuil.
+// L'état est conservé en mémoire : après un redémarrage, la longueur repart de la valeur configurée
+// et s'ajuste de nouveau dès les premières collisions.
+type growth struct {
+	mu          sync.Mutex
+	length      int
+	maxLength   int
+	threshold   float64
+	attempts    int
+	collisions  int
+	consecutive int
+}
+
+func newGrowth(length, maxLength int, threshold float64) *growth {
+	return &growth{length: length, maxLength: maxLength, threshold: threshold}
+}
+
+// current retourne la longueur actuelle des codes.
+func (g *growth) current() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.length
+}
+
+// Observe comptabilise une tentative. La longueur augmente dès que les collisions de la fenêtre
+// dépassent le seuil, ou après maxConsecutive collisions d'affilée ; la fenêtre est remise à zéro
+// après growthWindow tentatives.
+func (g *growth) Observe(collided bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.attempts++
+	if collided {
+		g.collisions++
+		g.consecutive++
+	} else {
+		g.consecutive = 0
+	}
+	if (float64(g.collisions) > g.threshold*growthWindow || g.consecutive >= maxConsecutive) && g.length < g.maxLength {
+		g.length++
+		log.Printf("[SHORTCODE] Taux de collision élevé (%d/%d), longueur des codes portée à %d.", g.collisions, g.attempts, g.length)
+		g.attempts, g.collisions, g.consecutive = 0, 0, 0
+	}
+	if g.attempts >= growthWindow {
+		g.attempts, g.collisions = 0, 0
+	}
+}
+
+// randomGenerator tire chaque caractère au hasard dans l'alphabet.
+type randomGenerator struct {
+	alphabet string
+	*growth
+}
+
+func (g *randomGenerator) Generate(Sequence) (string, error) {
+	return RandomString(g.alphabet, g.current())
+}
@@ -0,0 +1,40 @@
+package shortcode
+
+import "strings"
+
+// Lettres des codes prononçables : alternance consonne/voyelle ("kabemo").
+const (
+	consonants = "bdfghjklmnprstvz"
+	vowels     = "aeiou"
+)
+
+// pronounceableGenerator produit des codes faits de syllabes consonne + voyelle, faciles à dicter.
+type pronounceableGenerator struct {
+	consonants string
+	*growth
+}
+
+func newPronounceableGenerator(excludeAmbiguous bool, g *growth) *pronounceableGenerator {
+	letters := consonants
+	if excludeAmbiguous {
+		letters = strings.ReplaceAll(letters, "l", "")
+	}
+	return &pronounceableGenerator{consonants: letters, growth: g}
+}
+
+func (g *pronounceableGenerator) Generate(Sequence) (string, error) {
+	length := g.current()
+	var b strings.Builder
+	for b.Len() < length {
+		letters := g.consonants
+		if b.Len()%2 == 1 {
+			letters = vowels
+		}
+		c, err := RandomString(letters, 1)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(c)
+	}
+	return b.String(), nil
+}
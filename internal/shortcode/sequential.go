@@ -0,0 +1,125 @@
+package shortcode
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// sequenceName est le nom de la séquence persistée des codes séquentiels.
+const sequenceName = "short_code"
+
+// sequentialGenerator encode une valeur croissante de la séquence persistée. Chaque valeur étant
+// unique, les codes ne se répètent pas : seuls des codes déjà présents avant le changement de
+// stratégie peuvent entrer en collision, la valeur suivante est alors utilisée.
+type sequentialGenerator struct {
+	alphabet   string
+	minLength  int
+	obfuscated bool
+	salt       string
+}
+
+func newSequentialGenerator(alphabet string, minLength int, encoding, salt string) (*sequentialGenerator, error) {
+	g := &sequentialGenerator{alphabet: alphabet, minLength: minLength, salt: salt}
+	switch strings.ToLower(encoding) {
+	case "", EncodingBase62:
+	case EncodingObfuscated:
+		g.obfuscated = true
+		g.alphabet = shuffle(alphabet, salt)
+	default:
+		return nil, fmt.Errorf("%w: unknown encoding %q (base62, obfuscated)", ErrInvalidConfig, encoding)
+	}
+	return g, nil
+}
+
+func (g *sequentialGenerator) Generate(seq Sequence) (string, error) {
+	if seq == nil {
+		return "", fmt.Errorf("%w: sequential strategy requires a sequence", ErrInvalidConfig)
+	}
+	n, err := seq.NextSequenceValue(sequenceName)
+	if err != nil {
+		return "", fmt.Errorf("failed to read short code sequence: %w", err)
+	}
+	return g.encode(n)
+}
+
+// Observe est sans effet : la longueur ne dépend que de la valeur de la séquence.
+func (g *sequentialGenerator) Observe(bool) {}
+
+// encode écrit n en base len(alphabet) sur au moins minLength caractères.
+// En mode obfusqué, n est d'abord permuté dans l'espace des codes de même longueur
+// (n -> n*P + K mod base^longueur, P premier avec la base) : la transformation est bijective,
+// deux valeurs distinctes donnent donc toujours deux codes distincts.
+func (g *sequentialGenerator) encode(n uint64) (string, error) {
+	base := uint64(len(g.alphabet))
+	length := g.minLength
+	space, ok := power(base, length)
+	for ok && n >= space {
+		length++
+		space, ok = power(base, length)
+	}
+	if !ok || length > MaxLength {
+		return "", fmt.Errorf("short code sequence exhausted (%d)", n)
+	}
+
+	if g.obfuscated {
+		p, k := g.permutation(space)
+		hi, lo := bits.Mul64(n, p)
+		n = bits.Rem64(hi, lo, space)
+		n = (n + k) % space
+	}
+
+	code := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		code[i] = g.alphabet[n%base]
+		n /= base
+	}
+	return string(code), nil
+}
+
+// permutation dérive du sel un multiplicateur premier avec space et un décalage.
+func (g *sequentialGenerator) permutation(space uint64) (uint64, uint64) {
+	sum := sha256.Sum256([]byte(g.salt + "|" + fmt.Sprint(space)))
+	p := binary.BigEndian.Uint64(sum[:8])%space | 1
+	for gcd(p, space) != 1 {
+		p = (p + 2) % space
+	}
+	k := binary.BigEndian.Uint64(sum[8:16]) % space
+	return p, k
+}
+
+// power retourne base^exp, ok vaut false en cas de dépassement de capacité.
+func power(base uint64, exp int) (uint64, bool) {
+	result := uint64(1)
+	for i := 0; i < exp; i++ {
+		hi, lo := bits.Mul64(result, base)
+		if hi != 0 {
+			return 0, false
+		}
+		result = lo
+	}
+	return result, true
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// shuffle mélange l'alphabet de manière déterministe à partir du sel (Fisher-Yates).
+func shuffle(alphabet, salt string) string {
+	chars := []byte(alphabet)
+	state := sha256.Sum256([]byte(salt))
+	for i := len(chars) - 1; i > 0; i-- {
+		if i%8 == 0 {
+			state = sha256.Sum256(state[:])
+		}
+		j := int(binary.BigEndian.Uint32(state[(i%8)*4:(i%8)*4+4]) % uint32(i+1))
+		chars[i], chars[j] = chars[j], chars[i]
+	}
+	return string(chars)
+}
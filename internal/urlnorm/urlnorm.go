@@ -0,0 +1,74 @@
+// Package urlnorm met les URLs sous une forme canonique afin de reconnaître
+// deux écritures d'une même destination (déduplication des liens).
+package urlnorm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// trackingParams sont les paramètres de suivi retirés lors de la normalisation
+// (les paramètres utm_* sont retirés par préfixe).
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "gbraid": true, "wbraid": true, "msclkid": true,
+	"yclid": true, "twclid": true, "ttclid": true, "li_fat_id": true, "igshid": true,
+	"mc_cid": true, "mc_eid": true, "_ga": true, "_gl": true,
+}
+
+// defaultPorts associe chaque schéma à son port par défaut, retiré de l'URL canonique.
+var defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+// Canonicalize retourne la forme canonique de rawURL :
+//   - schéma et hôte en minuscules, point final de l'hôte et port par défaut retirés ;
+//   - chemin vide remplacé par "/" ;
+//   - paramètres de suivi (utm_*, fbclid, gclid...) retirés, paramètres restants triés par nom
+//     (l'ordre des valeurs répétées d'un même paramètre est conservé).
+//
+// Le fragment (#ancre) est conservé : il désigne une autre partie de la page.
+func Canonicalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("not an absolute URL: %q", rawURL)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]" // Adresse IPv6
+	}
+	if port := u.Port(); port != "" && port != defaultPorts[u.Scheme] {
+		host += ":" + port
+	}
+	u.Host = host
+
+	if u.Path == "" && u.Opaque == "" {
+		u.Path = "/"
+	}
+
+	query := u.Query()
+	for key := range query {
+		if trackingParams[strings.ToLower(key)] || strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode() // Encode trie les paramètres par nom
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+// Hash retourne l'empreinte SHA-256 (hexadécimale) de la forme canonique de rawURL.
+func Hash(rawURL string) (string, error) {
+	canonical, err := Canonicalize(rawURL)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,130 @@
+package useragent
+
+import "strings"
+
+// Plateformes (systèmes d'exploitation) reconnues.
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+	PlatformWindows = "windows"
+	PlatformMacOS   = "macos"
+	PlatformLinux   = "linux"
+	PlatformOther   = "other"
+)
+
+// Catégories d'appareils reconnues.
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	DeviceBot     = "bot"
+)
+
+// Info est le résultat de l'analyse d'un User-Agent.
+type Info struct {
+	Platform string // Système d'exploitation (ios, android, windows, macos, linux, other)
+	Device   string // Catégorie d'appareil (mobile, tablet, desktop, bot)
+}
+
+// botMarkers sont des fragments caractéristiques des robots et clients non interactifs.
+var botMarkers = []string{"bot", "crawler", "spider", "slurp", "curl/", "wget/", "python-requests", "go-http-client", "preview"}
+
+// unfurlBotMarkers identifient les robots qui génèrent l'aperçu d'un lien collé dans une messagerie
+// ou un réseau social (iMessage se présente comme facebookexternalhit et Twitterbot).
+// Les navigateurs intégrés aux applications (Pinterest, WhatsApp) citent aussi le nom de l'application :
+// seuls les marqueurs propres aux robots sont retenus.
+var unfurlBotMarkers = []string{
+	"facebookexternalhit", "facebot", "twitterbot", "slackbot", "slack-imgproxy", "discordbot",
+	"telegrambot", "linkedinbot", "skypeuripreview", "microsoftpreview", "pinterestbot", "pinterest/0.",
+	"redditbot", "embedly", "vkshare", "mastodon", "bluesky", "mattermost", "iframely",
+}
+
+// whatsAppMarker identifie le robot d'aperçu de WhatsApp ("WhatsApp/2.23.20.0 A"), à condition que le
+// User-Agent ne contienne aucun moteur de navigateur (browserEngineMarkers) : sinon c'est un visiteur
+// dans le navigateur intégré de l'application.
+const whatsAppMarker = "whatsapp/"
+
+// browserEngineMarkers sont les moteurs de rendu annoncés par les navigateurs.
+var browserEngineMarkers = []string{"applewebkit", "gecko/", "trident/", "presto/"}
+
+// Parse détermine la plateforme et la catégorie d'appareil à partir d'un User-Agent.
+// L'analyse repose sur des marqueurs simples et suffit au routage : elle ne cherche pas
+// à identifier le navigateur ni les versions.
+func Parse(userAgent string) Info {
+	ua := strings.ToLower(userAgent)
+	info := Info{Platform: PlatformOther, Device: DeviceDesktop}
+
+	switch {
+	case strings.Contains(ua, "ipad"):
+		info.Platform, info.Device = PlatformIOS, DeviceTablet
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipod"):
+		info.Platform, info.Device = PlatformIOS, DeviceMobile
+	case strings.Contains(ua, "android"):
+		info.Platform = PlatformAndroid
+		// Les tablettes Android n'annoncent pas "Mobile" dans leur User-Agent
+		if strings.Contains(ua, "mobile") {
+			info.Device = DeviceMobile
+		} else {
+			info.Device = DeviceTablet
+		}
+	case strings.Contains(ua, "windows"):
+		info.Platform = PlatformWindows
+	case strings.Contains(ua, "macintosh"), strings.Contains(ua, "mac os x"):
+		info.Platform = PlatformMacOS
+		// Safari sur iPadOS se présente comme un Mac ; seul l'écran tactile les distingue
+		// et il n'est pas visible côté serveur, l'appareil reste donc "desktop".
+	case strings.Contains(ua, "linux"), strings.Contains(ua, "x11"):
+		info.Platform = PlatformLinux
+	}
+
+	if IsBot(userAgent) {
+		info.Device = DeviceBot
+	}
+	return info
+}
+
+// IsBot indique si le User-Agent correspond à un robot (moteur de recherche, outil en ligne de commande...).
+func IsBot(userAgent string) bool {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return true
+	}
+	for _, marker := range botMarkers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsKnownTarget indique si la valeur est une plateforme ou une catégorie d'appareil utilisable dans une règle.
+func IsKnownTarget(target string) bool {
+	switch target {
+	case PlatformIOS, PlatformAndroid, PlatformWindows, PlatformMacOS, PlatformLinux,
+		DeviceMobile, DeviceTablet, DeviceDesktop:
+		return true
+	}
+	return false
+}
+
+// IsUnfurlBot indique si le User-Agent est celui d'un robot d'aperçu de lien (Slack, Discord,
+// WhatsApp, réseaux sociaux...). Ces robots lisent les balises Open Graph de la page.
+func IsUnfurlBot(userAgent string) bool {
+	ua := strings.ToLower(userAgent)
+	for _, marker := range unfurlBotMarkers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return strings.Contains(ua, whatsAppMarker) && !hasBrowserEngine(ua)
+}
+
+// hasBrowserEngine indique si le User-Agent (en minuscules) annonce un moteur de navigateur.
+func hasBrowserEngine(ua string) bool {
+	for _, marker := range browserEngineMarkers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return false
+}
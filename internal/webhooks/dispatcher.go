@@ -0,0 +1,401 @@
+// Package webhooks envoie les événements des liens (création, clics, santé) aux systèmes externes abonnés.
+// Chaque événement est enregistré comme une livraison par abonnement, puis envoyé de façon asynchrone
+// et signé (HMAC-SHA256) ; les échecs sont retentés avec un délai exponentiel.
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Quanghng/url-shortener/internal/config"
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
+)
+
+// userAgent identifie l'expéditeur auprès des destinataires.
+const userAgent = "url-shortener-webhooks/1.0"
+
+// En-têtes HTTP des livraisons
+const (
+	HeaderEvent     = "X-Webhook-Event"     // Type d'événement
+	HeaderEventID   = "X-Webhook-ID"        // Identifiant de l'événement (identique pour une nouvelle livraison : permet de dédupliquer)
+	HeaderDelivery  = "X-Webhook-Delivery"  // Identifiant de la livraison
+	HeaderTimestamp = "X-Webhook-Timestamp" // Date d'envoi (secondes Unix), incluse dans la signature
+	HeaderSignature = "X-Webhook-Signature" // "sha256=" + HMAC-SHA256 hexadécimal de "<timestamp>.<corps>"
+)
+
+// maxErrorLength borne la taille des erreurs et extraits de réponse conservés dans le journal.
+const maxErrorLength = 500
+
+// subscriptionsTTL est la durée de validité du cache des abonnements. Le cache est invalidé
+// par les créations et suppressions faites par ce processus ; le délai couvre celles de la CLI.
+const subscriptionsTTL = 30 * time.Second
+
+// pollBatchSize est le nombre maximal de livraisons dues reprises à chaque passage du poller.
+const pollBatchSize = 100
+
+// Event est l'enveloppe JSON envoyée aux abonnés.
+type Event struct {
+	ID        string      `json:"id"`         // Identifiant unique de l'événement
+	Type      string      `json:"type"`       // Type d'événement (voir models.WebhookEvents)
+	CreatedAt time.Time   `json:"created_at"` // Date de l'événement
+	Data      interface{} `json:"data"`       // Données propres au type d'événement
+}
+
+// Dispatcher enregistre les livraisons des événements publiés et les envoie aux abonnés.
+// Un Dispatcher non démarré (CLI) se contente d'enregistrer les livraisons :
+// elles sont envoyées par le poller du serveur.
+type Dispatcher struct {
+	repo           repository.WebhookRepository
+	client         *http.Client
+	queue          chan uint     // Livraisons à tenter immédiatement (nil tant que le Dispatcher n'est pas démarré)
+	events         chan Event    // Événements publiés à répartir entre les abonnés (nil tant que le Dispatcher n'est pas démarré)
+	workers        int           // Nombre de workers d'envoi
+	maxAttempts    int           // Nombre maximal de tentatives d'une livraison
+	initialBackoff time.Duration // Délai avant la deuxième tentative, doublé à chaque échec
+	maxBackoff     time.Duration // Délai maximal entre deux tentatives
+	pollInterval   time.Duration // Intervalle de reprise des livraisons dues
+	lease          time.Duration // Durée de réservation d'une livraison pendant une tentative
+
+	mu       sync.RWMutex                 // Protège le cache des abonnements
+	subs     []models.WebhookSubscription // Cache des abonnements (voir subscriptions)
+	loadedAt time.Time                    // Date du chargement du cache (zéro = à charger)
+}
+
+// NewDispatcher crée un Dispatcher utilisant une copie de client, qui doit porter les protections réseau voulues
+// (policy.DestinationPolicy.NewHTTPClient en production). Les redirections ne sont jamais suivies :
+// une réponse 3xx est un échec, sans quoi le POST signé deviendrait un GET sans corps.
+func NewDispatcher(repo repository.WebhookRepository, client *http.Client, cfg config.WebhooksConfig) *Dispatcher {
+	noRedirect := *client
+	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	d := &Dispatcher{
+		repo:           repo,
+		client:         &noRedirect,
+		workers:        cfg.Workers,
+		maxAttempts:    cfg.MaxAttempts,
+		initialBackoff: time.Duration(cfg.InitialBackoffSeconds) * time.Second,
+		maxBackoff:     time.Duration(cfg.MaxBackoffSeconds) * time.Second,
+		pollInterval:   time.Duration(cfg.PollIntervalSeconds) * time.Second,
+		lease:          2*client.Timeout + time.Minute,
+	}
+	if d.workers <= 0 {
+		d.workers = 1
+	}
+	if d.maxAttempts <= 0 {
+		d.maxAttempts = 1
+	}
+	if d.initialBackoff <= 0 {
+		d.initialBackoff = 10 * time.Second
+	}
+	if d.maxBackoff < d.initialBackoff {
+		d.maxBackoff = d.initialBackoff
+	}
+	if d.pollInterval <= 0 {
+		d.pollInterval = 5 * time.Second
+	}
+	return d
+}
+
+// Start lance les workers d'envoi, la goroutine de répartition des événements publiés
+// et le poller qui reprend les livraisons dues (nouvelles tentatives, livraisons enregistrées par la CLI,
+// file pleine, redémarrage du serveur).
+func (d *Dispatcher) Start(queueSize int) {
+	d.queue = make(chan uint, queueSize)
+	d.events = make(chan Event, queueSize)
+	log.Printf("Starting %d webhook worker(s)...", d.workers)
+	for i := 0; i < d.workers; i++ {
+		go d.worker()
+	}
+	go d.fanOut()
+	go d.poll()
+}
+
+// Publish publie un événement aux abonnements concernés. Sans abonné à l'événement, il ne fait rien ;
+// sinon, sur un Dispatcher démarré, l'événement est confié sans bloquer à la goroutine de répartition
+// (les workers de clics ne font aucune écriture en base), et sur un Dispatcher non démarré (CLI),
+// les livraisons sont enregistrées immédiatement.
+// Il ne fait rien sur un Dispatcher nil ; les erreurs sont journalisées sans interrompre l'appelant.
+func (d *Dispatcher) Publish(eventType string, data interface{}) {
+	if d == nil || len(d.targets(eventType)) == 0 {
+		return
+	}
+	event := Event{ID: newEventID(), Type: eventType, CreatedAt: time.Now().UTC(), Data: data}
+	if d.events == nil {
+		d.record(event)
+		return
+	}
+	select {
+	case d.events <- event:
+	default:
+		log.Printf("Warning: webhook event queue is full, dropping %s event %s.", event.Type, event.ID)
+	}
+}
+
+// InvalidateSubscriptions vide le cache des abonnements, après une création ou une suppression.
+func (d *Dispatcher) InvalidateSubscriptions() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	d.loadedAt = time.Time{}
+	d.mu.Unlock()
+}
+
+// targets retourne les abonnements à l'événement, depuis le cache (rechargé après subscriptionsTTL).
+func (d *Dispatcher) targets(eventType string) []models.WebhookSubscription {
+	d.mu.RLock()
+	subs, fresh := d.subs, !d.loadedAt.IsZero() && time.Since(d.loadedAt) < subscriptionsTTL
+	d.mu.RUnlock()
+	if !fresh {
+		loaded, err := d.repo.ListSubscriptions()
+		if err != nil {
+			log.Printf("ERROR: Failed to list webhook subscriptions for %s: %v", eventType, err)
+			return nil
+		}
+		d.mu.Lock()
+		d.subs, d.loadedAt, subs = loaded, time.Now(), loaded
+		d.mu.Unlock()
+	}
+
+	var targets []models.WebhookSubscription
+	for _, sub := range subs {
+		if sub.Accepts(eventType) {
+			targets = append(targets, sub)
+		}
+	}
+	return targets
+}
+
+// fanOut enregistre les livraisons des événements publiés sur un Dispatcher démarré.
+func (d *Dispatcher) fanOut() {
+	for event := range d.events {
+		d.record(event)
+	}
+}
+
+// record enregistre une livraison de l'événement pour chaque abonnement concerné
+// et la place dans la file d'envoi.
+func (d *Dispatcher) record(event Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("ERROR: Failed to encode webhook event %s: %v", event.Type, err)
+		return
+	}
+	for _, sub := range d.targets(event.Type) {
+		now := time.Now()
+		delivery := &models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			Event:          event.Type,
+			Payload:        string(payload),
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  &now,
+		}
+		if err := d.repo.CreateDelivery(delivery); err != nil {
+			log.Printf("ERROR: Failed to save webhook delivery for subscription %d: %v", sub.ID, err)
+			continue
+		}
+		d.enqueue(delivery.ID)
+	}
+}
+
+// Redeliver enregistre via deliveries (le dépôt d'une transaction en cours, par exemple) une nouvelle
+// livraison de la livraison original : même événement, même contenu. Une fois la transaction validée,
+// Enqueue la place dans la file d'envoi ; à défaut, le poller la reprend.
+func (d *Dispatcher) Redeliver(deliveries repository.WebhookRepository, original *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	now := time.Now()
+	originalID := original.ID
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: original.SubscriptionID,
+		EventID:        original.EventID,
+		Event:          original.Event,
+		Payload:        original.Payload,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  &now,
+		RedeliveryOf:   &originalID,
+	}
+	if err := deliveries.CreateDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Enqueue place une livraison enregistrée dans la file d'envoi (voir Redeliver).
+func (d *Dispatcher) Enqueue(id uint) {
+	d.enqueue(id)
+}
+
+// enqueue place une livraison dans la file d'envoi sans bloquer.
+// Si la file est pleine (ou le Dispatcher non démarré), la livraison sera reprise par le poller.
+func (d *Dispatcher) enqueue(id uint) {
+	if d.queue == nil {
+		return
+	}
+	select {
+	case d.queue <- id:
+	default:
+		log.Printf("Warning: webhook queue is full, delivery %d will be picked up by the poller.", id)
+	}
+}
+
+// worker effectue les tentatives des livraisons reçues dans la file.
+func (d *Dispatcher) worker() {
+	for id := range d.queue {
+		d.attempt(id)
+	}
+}
+
+// poll place périodiquement dans la file les livraisons dont la tentative est due.
+func (d *Dispatcher) poll() {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ids, err := d.repo.ListDueDeliveries(time.Now(), pollBatchSize)
+		if err != nil {
+			log.Printf("ERROR: Failed to list due webhook deliveries: %v", err)
+			continue
+		}
+		for _, id := range ids {
+			d.enqueue(id)
+		}
+	}
+}
+
+// attempt réserve la livraison, l'envoie et enregistre le résultat :
+// succès, nouvelle tentative après le délai exponentiel, ou échec définitif après maxAttempts.
+func (d *Dispatcher) attempt(id uint) {
+	claimed, err := d.repo.ClaimDelivery(id, time.Now(), d.lease)
+	if err != nil {
+		log.Printf("ERROR: Failed to claim webhook delivery %d: %v", id, err)
+		return
+	}
+	if !claimed {
+		return // Déjà envoyée, ou en cours d'envoi par un autre worker
+	}
+	delivery, err := d.repo.GetDelivery(id)
+	if err != nil {
+		log.Printf("ERROR: Failed to load webhook delivery %d: %v", id, err)
+		return
+	}
+
+	delivery.Attempts++
+	statusCode, sendErr := d.send(delivery)
+	delivery.LastStatusCode = statusCode
+	now := time.Now()
+	switch {
+	case sendErr == nil:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= d.maxAttempts:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = nil
+		log.Printf("[WEBHOOK] Livraison %d (%s) abandonnée après %d tentative(s): %v", delivery.ID, delivery.Event, delivery.Attempts, sendErr)
+	default:
+		next := now.Add(d.backoff(delivery.Attempts))
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = &next
+		log.Printf("[WEBHOOK] Échec de la livraison %d (%s), tentative %d/%d, nouvelle tentative le %s: %v",
+			delivery.ID, delivery.Event, delivery.Attempts, d.maxAttempts, next.Format(time.RFC3339), sendErr)
+	}
+	if err := d.repo.UpdateDelivery(delivery); err != nil {
+		log.Printf("ERROR: Failed to save webhook delivery %d: %v", delivery.ID, err)
+	}
+}
+
+// backoff retourne le délai avant la tentative suivant la tentative n (n >= 1) :
+// initialBackoff × 2^(n-1), borné par maxBackoff.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.initialBackoff
+	for i := 1; i < attempt && delay < d.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.maxBackoff {
+		delay = d.maxBackoff
+	}
+	return delay
+}
+
+// send effectue une tentative de livraison et retourne le code HTTP de la réponse (0 sans réponse).
+// Seule une réponse 2xx est un succès ; les redirections ne sont pas suivies.
+func (d *Dispatcher) send(delivery *models.WebhookDelivery) (int, error) {
+	sub, err := d.repo.GetSubscription(delivery.SubscriptionID)
+	if err != nil {
+		return 0, fmt.Errorf("subscription %d unavailable: %w", delivery.SubscriptionID, err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, sub.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, []byte(delivery.Payload)))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, truncate(err.Error())
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, truncate(fmt.Sprintf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(body)))
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign calcule la signature d'une livraison : "sha256=" suivi du HMAC-SHA256 hexadécimal,
+// avec le secret de l'abonnement, de "<timestamp>.<corps>".
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret génère un secret de signature aléatoire.
+func NewSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// newEventID génère un identifiant d'événement aléatoire.
+func newEventID() string {
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return "evt_" + hex.EncodeToString(buf)
+}
+
+// truncate convertit un message en erreur de taille bornée pour le journal des livraisons.
+func truncate(message string) error {
+	if len(message) > maxErrorLength {
+		message = strings.ToValidUTF8(message[:maxErrorLength], "") + "…"
+	}
+	return errors.New(message)
+}
@@ -5,22 +5,24 @@ import (
 
 	"github.com/Quanghng/url-shortener/internal/models"
 	"github.com/Quanghng/url-shortener/internal/repository" // Nécessaire pour interagir avec le ClickRepository
+	"github.com/Quanghng/url-shortener/internal/webhooks"   // Publication des clics aux webhooks abonnés
 )
 
 // StartClickWorkers lance un pool de goroutines "workers" pour traiter les événements de clic.
 // Chaque worker lira depuis le même 'clickEventsChan' et utilisera le 'clickRepo' pour la persistance.
-func StartClickWorkers(workerCount int, clickEventsChan <-chan models.ClickEvent, clickRepo repository.ClickRepository) {
+// Chaque clic enregistré est publié (link.clicked) via dispatcher, qui peut être nil.
+func StartClickWorkers(workerCount int, clickEventsChan <-chan models.ClickEvent, clickRepo repository.ClickRepository, dispatcher *webhooks.Dispatcher) {
 	log.Printf("Starting %d click worker(s)...", workerCount)
 	for i := 0; i < workerCount; i++ {
 		// Lance chaque worker dans sa propre goroutine.
 		// Le channel est passé en lecture seule (<-chan) pour renforcer l'immutabilité du channel à l'intérieur du worker.
-		go clickWorker(clickEventsChan, clickRepo)
+		go clickWorker(clickEventsChan, clickRepo, dispatcher)
 	}
 }
 
 // clickWorker est la fonction exécutée par chaque goroutine worker.
 // Elle tourne indéfiniment, lisant les événements de clic dès qu'ils sont disponibles dans le channel.
-func clickWorker(clickEventsChan <-chan models.ClickEvent, clickRepo repository.ClickRepository) {
+func clickWorker(clickEventsChan <-chan models.ClickEvent, clickRepo repository.ClickRepository, dispatcher *webhooks.Dispatcher) {
 	for event := range clickEventsChan { // Boucle qui lit les événements du channel
 		// Convertit le 'ClickEvent' (reçu du channel) en un modèle 'models.Click'
 		click := &models.Click{
@@ -28,11 +30,16 @@ func clickWorker(clickEventsChan <-chan models.ClickEvent, clickRepo repository.
 			Timestamp: event.Timestamp,
 			UserAgent: event.UserAgent,
 			IPAddress: event.IPAddress,
+			Country:   event.Country,
+			Platform:  event.Platform,
+			Device:    event.Device,
+			Variant:   event.Variant,
+			Source:    event.Source,
 		}
 
 		// Persiste le clic en base de données via le 'clickRepo'
 		err := clickRepo.CreateClick(click)
-		
+
 		if err != nil {
 			// Si une erreur se produit lors de l'enregistrement, logguez-la.
 			// L'événement est "perdu" pour ce TP, mais dans un vrai système,
@@ -43,6 +50,20 @@ func clickWorker(clickEventsChan <-chan models.ClickEvent, clickRepo repository.
 		} else {
 			// Log optionnel pour confirmer l'enregistrement (utile pour le débogage)
 			log.Printf("Click recorded successfully for LinkID %d", event.LinkID)
+
+			// Publie le clic enregistré aux webhooks abonnés (sans l'adresse IP du visiteur) ;
+			// les livraisons sont enregistrées par le dispatcher, hors du worker de clics
+			dispatcher.Publish(models.WebhookLinkClicked, models.WebhookClickData{
+				ShortCode: event.ShortCode,
+				Domain:    event.Domain,
+				Timestamp: event.Timestamp.UTC(),
+				UserAgent: event.UserAgent,
+				Country:   event.Country,
+				Platform:  event.Platform,
+				Device:    event.Device,
+				Variant:   event.Variant,
+				Source:    event.Source,
+			})
 		}
 	}
 }
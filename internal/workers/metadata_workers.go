@@ -0,0 +1,48 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Quanghng/url-shortener/internal/metadata"
+	"github.com/Quanghng/url-shortener/internal/models"
+	"github.com/Quanghng/url-shortener/internal/repository"
+)
+
+// StartMetadataWorkers lance un pool de goroutines qui récupèrent les métadonnées
+// des destinations des liens nouvellement créés.
+func StartMetadataWorkers(workerCount int, jobs <-chan models.MetadataJob, fetcher *metadata.Fetcher, linkRepo repository.LinkRepository) {
+	log.Printf("Starting %d metadata worker(s)...", workerCount)
+	for i := 0; i < workerCount; i++ {
+		go metadataWorker(jobs, fetcher, linkRepo)
+	}
+}
+
+// metadataWorker traite les demandes de récupération de métadonnées dès qu'elles arrivent dans le channel.
+func metadataWorker(jobs <-chan models.MetadataJob, fetcher *metadata.Fetcher, linkRepo repository.LinkRepository) {
+	for job := range jobs {
+		if _, err := FetchLinkMetadata(fetcher, linkRepo, job); err != nil {
+			log.Printf("ERROR: Failed to save metadata for LinkID %d: %v", job.LinkID, err)
+		}
+	}
+}
+
+// FetchLinkMetadata récupère les métadonnées de la destination d'un lien et les enregistre.
+// Un échec de récupération (page inaccessible, contenu non HTML) est conservé dans LinkMetadata.Error ;
+// l'erreur retournée ne concerne que l'enregistrement en base. La durée est bornée par le timeout du client HTTP du fetcher.
+func FetchLinkMetadata(fetcher *metadata.Fetcher, linkRepo repository.LinkRepository, job models.MetadataJob) (models.LinkMetadata, error) {
+	now := time.Now()
+	result := models.LinkMetadata{FetchedAt: &now}
+	meta, err := fetcher.Fetch(context.Background(), job.LongURL)
+	if err != nil {
+		log.Printf("[METADATA] Récupération impossible pour le lien %d (%s): %v", job.LinkID, job.LongURL, err)
+		result.Error = err.Error()
+	} else {
+		result.Title = meta.Title
+		result.Description = meta.Description
+		result.FaviconURL = meta.FaviconURL
+		result.ImageURL = meta.ImageURL
+	}
+	return result, linkRepo.UpdateMetadata(job.LinkID, result)
+}
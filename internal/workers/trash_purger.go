@@ -0,0 +1,30 @@
+package workers
+
+import (
+	"log"
+	"time"
+
+	"github.com/Quanghng/url-shortener/internal/services"
+)
+
+// trashPurgeActor est l'auteur des purges automatiques dans le journal d'audit.
+const trashPurgeActor = "system:trash"
+
+// StartTrashPurger lance une goroutine qui purge périodiquement les liens restés à la corbeille
+// au-delà de la durée de conservation (voir LinkService.SetTrashRetention). Une première purge
+// est effectuée au démarrage.
+func StartTrashPurger(linkService *services.LinkService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			purged, err := linkService.PurgeTrash(false, trashPurgeActor)
+			if err != nil {
+				log.Printf("ERROR: Failed to purge trash: %v", err)
+			} else if purged > 0 {
+				log.Printf("[TRASH] %d lien(s) purgé(s) définitivement de la corbeille.", purged)
+			}
+			<-ticker.C
+		}
+	}()
+}